@@ -0,0 +1,221 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+const defaultBroadcastConcurrency = 10
+
+// 群发消息发送状态
+const (
+	BroadcastStatusSent    = "sent"    // 发送成功
+	BroadcastStatusSkipped = "skipped" // 客户的48小时发送时限已过或额度已用完，未发送
+	BroadcastStatusFailed  = "failed"  // 发送失败
+)
+
+// BroadcastRecipient 群发消息接收人
+type BroadcastRecipient struct {
+	OpenKFID       string // 发送消息的客服帐号ID
+	ExternalUserID string // 接收消息的客户UserID
+}
+
+// BroadcastMessage 根据接收人生成消息，mediaID 为已上传素材的media_id，未指定素材时为空
+type BroadcastMessage func(toUser, openKFID, mediaID string) interface{}
+
+// BroadcastOptions 群发消息参数
+type BroadcastOptions struct {
+	Recipients  []BroadcastRecipient // 接收人列表
+	Message     BroadcastMessage     // 消息内容，为空时根据 MediaType 发送素材消息
+	MediaType   string               // 素材类型，支持：image、voice、video、file，指定时必须同时指定 Media
+	Media       *MediaSource         // 素材来源，群发前上传一次，所有接收人复用同一个media_id
+	Concurrency int                  // 并发发送数，默认10
+	Rate        int                  // 每秒最多发送条数，为0时不限制
+}
+
+// BroadcastResult 单个接收人的发送结果
+type BroadcastResult struct {
+	BroadcastRecipient
+	Status string // 发送状态
+	MsgID  string // 发送成功时的消息ID
+	Err    error  // 发送失败的原因
+}
+
+// BroadcastReport 群发结果，Results 与接收人列表顺序一致
+type BroadcastReport struct {
+	Results []BroadcastResult
+}
+
+// Count 统计指定状态的接收人数量
+func (r BroadcastReport) Count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteCSV 以CSV格式导出群发结果
+func (r BroadcastReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"open_kfid", "external_userid", "status", "msgid", "error"}); err != nil {
+		return err
+	}
+	for _, result := range r.Results {
+		errMsg := ""
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		}
+		if err := writer.Write([]string{result.OpenKFID, result.ExternalUserID, result.Status, result.MsgID, errMsg}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Broadcast 向多个客户发送相同内容的消息
+// 开启 IsEnableQuota 时会跳过发送时限已过或额度已用完的客户，无消息记录的客户仍会尝试发送
+// ctx 取消后未发送的接收人记为发送失败
+// 参数组合在上传素材和发送前校验：素材类型不支持时返回 SDKUnsupportedMediaType，缺少素材来源时返回 SDKMediaSourceMissing
+func (r *Client) Broadcast(ctx context.Context, options BroadcastOptions) (report BroadcastReport, err error) {
+	message, err := broadcastMessage(options)
+	if err != nil {
+		return report, err
+	}
+	mediaID := ""
+	if options.Media != nil {
+		if mediaID, err = r.UploadMedia(options.MediaType, *options.Media); err != nil {
+			return report, err
+		}
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaultBroadcastConcurrency
+	}
+
+	var limiter <-chan time.Time
+	if options.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(options.Rate))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	report.Results = make([]BroadcastResult, len(options.Recipients))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				recipient := options.Recipients[index]
+				report.Results[index] = r.broadcastTo(recipient, message(recipient.ExternalUserID, recipient.OpenKFID, mediaID))
+			}
+		}()
+	}
+
+	for index, recipient := range options.Recipients {
+		if err = broadcastWait(ctx, limiter); err == nil {
+			select {
+			case indexes <- index:
+				continue
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		report.Results[index] = BroadcastResult{BroadcastRecipient: recipient, Status: BroadcastStatusFailed, Err: err}
+	}
+	close(indexes)
+	wg.Wait()
+	return report, nil
+}
+
+// broadcastTo 向单个客户发送消息
+func (r *Client) broadcastTo(recipient BroadcastRecipient, message interface{}) BroadcastResult {
+	result := BroadcastResult{BroadcastRecipient: recipient}
+	if r.isEnableQuota {
+		quota, err := r.QuotaGet(recipient.OpenKFID, recipient.ExternalUserID)
+		if err != nil {
+			result.Status, result.Err = BroadcastStatusFailed, err
+			return result
+		}
+		if quota.IsTracked && quota.Remaining <= 0 {
+			result.Status = BroadcastStatusSkipped
+			return result
+		}
+	}
+	info, err := r.SendMsg(message)
+	if err != nil {
+		result.Status, result.Err = BroadcastStatusFailed, err
+		return result
+	}
+	result.Status, result.MsgID = BroadcastStatusSent, info.MsgID
+	return result
+}
+
+// broadcastWait 按发送速率等待
+func broadcastWait(ctx context.Context, limiter <-chan time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if limiter == nil {
+		return nil
+	}
+	select {
+	case <-limiter:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// broadcastMessage 校验消息内容与素材参数的组合，返回用于生成消息的函数
+func broadcastMessage(options BroadcastOptions) (BroadcastMessage, error) {
+	if options.MediaType == "" && options.Media == nil {
+		if options.Message == nil {
+			return nil, NewSDKErr(50009)
+		}
+		return options.Message, nil
+	}
+	message, err := broadcastMediaMessage(options.MediaType)
+	if err != nil {
+		return nil, err
+	}
+	if options.Media == nil {
+		return nil, NewSDKErr(50009)
+	}
+	if options.Message != nil {
+		return options.Message, nil
+	}
+	return message, nil
+}
+
+// broadcastMediaMessage 根据素材类型生成消息
+func broadcastMediaMessage(mediaType string) (BroadcastMessage, error) {
+	switch mediaType {
+	case "image":
+		return func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewImage(toUser, openKFID, mediaID)
+		}, nil
+	case "voice":
+		return func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewVoice(toUser, openKFID, mediaID)
+		}, nil
+	case "video":
+		return func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewVideo(toUser, openKFID, mediaID)
+		}, nil
+	case "file":
+		return func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewFile(toUser, openKFID, mediaID)
+		}, nil
+	}
+	return nil, NewSDKErr(50008)
+}
@@ -0,0 +1,203 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+func TestBroadcastValidatesOptions(t *testing.T) {
+	textMessage := func(toUser, openKFID, mediaID string) interface{} {
+		return sendmsg.NewText(toUser, openKFID, "hello")
+	}
+	source := MediaFromReader("image.png", bytes.NewBufferString("image"))
+	tests := []struct {
+		name    string
+		options BroadcastOptions
+		wantErr error
+	}{
+		{name: "nothing to send", options: BroadcastOptions{}, wantErr: SDKMediaSourceMissing},
+		{name: "unsupported media type", options: BroadcastOptions{MediaType: "gif", Media: &source}, wantErr: SDKUnsupportedMediaType},
+		{name: "media without type", options: BroadcastOptions{Media: &source}, wantErr: SDKUnsupportedMediaType},
+		{name: "media type without media", options: BroadcastOptions{MediaType: "image"}, wantErr: SDKMediaSourceMissing},
+		{name: "message with unsupported media type", options: BroadcastOptions{Message: textMessage, MediaType: "gif", Media: &source}, wantErr: SDKUnsupportedMediaType},
+		{name: "message with media type only", options: BroadcastOptions{Message: textMessage, MediaType: "image"}, wantErr: SDKMediaSourceMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMediaServer(t)
+			client := newTestClient(t, Options{CorpID: "corp"})
+			tt.options.Recipients = []BroadcastRecipient{{OpenKFID: "kf", ExternalUserID: "user"}}
+
+			if _, err := client.Broadcast(context.Background(), tt.options); err != tt.wantErr {
+				t.Fatalf("Broadcast() err = %v, want %v", err, tt.wantErr)
+			}
+			if len(server.uploads) != 0 || len(server.mediaIDs) != 0 {
+				t.Fatalf("uploads = %v, sends = %v, want none", server.uploads, server.mediaIDs)
+			}
+		})
+	}
+}
+
+func TestBroadcastMedia(t *testing.T) {
+	server := newMediaServer(t)
+	client := newTestClient(t, Options{CorpID: "corp"})
+	source := MediaFromReader("image.png", bytes.NewBufferString("image"))
+
+	report, err := client.Broadcast(context.Background(), BroadcastOptions{
+		Recipients: []BroadcastRecipient{{OpenKFID: "kf", ExternalUserID: "a"}, {OpenKFID: "kf", ExternalUserID: "b"}},
+		MediaType:  "image",
+		Media:      &source,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(BroadcastStatusSent) != 2 {
+		t.Fatalf("results = %+v, want 2 sent", report.Results)
+	}
+	if len(server.uploads) != 1 || !equalStrings(server.mediaIDs, []string{"media-1", "media-1"}) {
+		t.Fatalf("uploads = %v, media ids = %v, want one upload reused", server.uploads, server.mediaIDs)
+	}
+}
+
+func TestBroadcastConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+	})
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	var recipients []BroadcastRecipient
+	for _, user := range []string{"a", "b", "c", "d", "e", "f"} {
+		recipients = append(recipients, BroadcastRecipient{OpenKFID: "kf", ExternalUserID: user})
+	}
+	report, err := client.Broadcast(context.Background(), BroadcastOptions{
+		Recipients: recipients,
+		Message: func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewText(toUser, openKFID, "hello")
+		},
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(BroadcastStatusSent) != len(recipients) {
+		t.Fatalf("results = %+v, want all sent", report.Results)
+	}
+	for index, result := range report.Results {
+		if result.ExternalUserID != recipients[index].ExternalUserID || result.MsgID == "" {
+			t.Fatalf("result %d = %+v, want recipient order and msgid", index, result)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 2 {
+		t.Fatalf("max concurrent sends = %d, want at most 2", max)
+	}
+}
+
+func TestBroadcastSkipsExhaustedQuota(t *testing.T) {
+	mutex := sync.Mutex{}
+	var sentTo []string
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body := struct {
+			ToUser string `json:"touser"`
+		}{}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		mutex.Lock()
+		sentTo = append(sentTo, body.ToUser)
+		mutex.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+	})
+	client := newTestClient(t, Options{CorpID: "corp", IsEnableQuota: true})
+	setQuota(t, client, "kf", "exhausted", time.Now(), quotaMaxMsgCount)
+	setQuota(t, client, "kf", "expired", time.Now().Add(-quotaWindow-time.Minute), 0)
+	setQuota(t, client, "kf", "available", time.Now(), 1)
+
+	report, err := client.Broadcast(context.Background(), BroadcastOptions{
+		Recipients: []BroadcastRecipient{
+			{OpenKFID: "kf", ExternalUserID: "exhausted"},
+			{OpenKFID: "kf", ExternalUserID: "expired"},
+			{OpenKFID: "kf", ExternalUserID: "available"},
+			{OpenKFID: "kf", ExternalUserID: "untracked"},
+		},
+		Message: func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewText(toUser, openKFID, "hello")
+		},
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{BroadcastStatusSkipped, BroadcastStatusSkipped, BroadcastStatusSent, BroadcastStatusSent}
+	for index, result := range report.Results {
+		if result.Status != want[index] {
+			t.Fatalf("result %d = %+v, want %s", index, result, want[index])
+		}
+	}
+	if !equalStrings(sentTo, []string{"available", "untracked"}) {
+		t.Fatalf("sent to %v, want [available untracked]", sentTo)
+	}
+}
+
+func TestBroadcastCanceled(t *testing.T) {
+	requests := int32(0)
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+	})
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := client.Broadcast(ctx, BroadcastOptions{
+		Recipients: []BroadcastRecipient{{OpenKFID: "kf", ExternalUserID: "a"}, {OpenKFID: "kf", ExternalUserID: "b"}},
+		Message: func(toUser, openKFID, mediaID string) interface{} {
+			return sendmsg.NewText(toUser, openKFID, "hello")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, result := range report.Results {
+		if result.Status != BroadcastStatusFailed || result.Err != context.Canceled {
+			t.Fatalf("result %d = %+v, want failed with context.Canceled", index, result)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("requests = %d, want 0", n)
+	}
+}
+
+func TestBroadcastReportWriteCSV(t *testing.T) {
+	report := BroadcastReport{Results: []BroadcastResult{
+		{BroadcastRecipient: BroadcastRecipient{OpenKFID: "kf", ExternalUserID: "a"}, Status: BroadcastStatusSent, MsgID: "m1"},
+		{BroadcastRecipient: BroadcastRecipient{OpenKFID: "kf", ExternalUserID: "b"}, Status: BroadcastStatusSkipped},
+		{BroadcastRecipient: BroadcastRecipient{OpenKFID: "kf", ExternalUserID: "c"}, Status: BroadcastStatusFailed, Err: SDKQuotaExceeded},
+	}}
+	buf := bytes.Buffer{}
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "open_kfid,external_userid,status,msgid,error\n" +
+		"kf,a,sent,m1,\n" +
+		"kf,b,skipped,,\n" +
+		"kf,c,failed,," + SDKQuotaExceeded.Error() + "\n"
+	if buf.String() != want {
+		t.Fatalf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+}
@@ -2,39 +2,64 @@ package WeChatCustomerServiceSDK
 
 import (
 	"github.com/NICEXAI/WeChatCustomerServiceSDK/cache"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/crypto"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgstore"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
 	"sync"
 	"time"
 )
 
 // BaseModel 基础数据
 type BaseModel struct {
-	ErrCode int64    `json:"errcode"` // 出错返回码，为0表示成功，非0表示调用失败
+	ErrCode int64  `json:"errcode"` // 出错返回码，为0表示成功，非0表示调用失败
 	ErrMsg  string `json:"errmsg"`  // 返回码提示语
 }
 
 // Options 微信客服初始化参数
 type Options struct {
-	CorpID         string        // 企业ID：企业开通的每个微信客服，都对应唯一的企业ID，企业可在微信客服管理后台的企业信息处查看
-	Secret         string        // Secret是微信客服用于校验开发者身份的访问密钥，企业成功注册微信客服后，可在「微信客服管理后台-开发配置」处获取
-	Token          string        // 用于生成签名校验回调请求的合法性
-	EncodingAESKey string        // 回调消息加解密参数是AES密钥的Base64编码，用于解密回调消息内容对应的密文
-	Cache          cache.Cache   // 数据缓存
-	ExpireTime     time.Duration // 令牌过期时间
-	IsCloseCache   bool          // 是否关闭自动缓存AccessToken, 默认缓存
+	CorpID          string                   // 企业ID：企业开通的每个微信客服，都对应唯一的企业ID，企业可在微信客服管理后台的企业信息处查看
+	Secret          string                   // Secret是微信客服用于校验开发者身份的访问密钥，企业成功注册微信客服后，可在「微信客服管理后台-开发配置」处获取
+	Token           string                   // 用于生成签名校验回调请求的合法性
+	EncodingAESKey  string                   // 回调消息加解密参数是AES密钥的Base64编码，用于解密回调消息内容对应的密文
+	PreviousKeys    []CryptoKey              // 轮换EncodingAESKey后仍可能收到回调的历史配置，解密时在主配置失败后按顺序依次尝试，Token为空时使用主配置的Token
+	Cache           cache.Cache              // 数据缓存
+	ExpireTime      time.Duration            // 令牌过期时间
+	IsCloseCache    bool                     // 是否关闭自动缓存AccessToken, 默认缓存
+	CursorStore     CursorStore              // 消息拉取游标存储，默认保存在Cache中
+	DedupExpireTime time.Duration            // 已处理消息msgid的记录有效期，如 24 * time.Hour，为0时不开启消息去重
+	MessageStore    msgstore.Store           // 会话记录存储，配置后自动保存拉取和发送的消息
+	IsEnableQuota   bool                     // 是否跟踪客户48小时内5条消息的发送额度，开启后额度不足时SendMsg直接返回错误
+	MsgIDGenerator  func(data []byte) string // 发送消息未指定msgid时的生成函数，参数为请求内容，默认随机生成，可使用 HashMsgID 生成确定的msgid
+	// DuplicateMsgIDErrCodes 接口因msgid重复拒绝请求时返回的错误码，命中时视为该msgid已在之前的请求中发送成功并返回成功
+	DuplicateMsgIDErrCodes []int64
+	// IsSkipReplyStateCheck 回复消息前是否跳过会话状态校验，跳过后每次回复少一次接口请求，会话状态不允许时由发送消息接口返回错误
+	IsSkipReplyStateCheck bool
 }
 
 // Client 微信客服实例
 type Client struct {
-	corpID         string        // 企业ID：企业开通的每个微信客服，都对应唯一的企业ID，企业可在微信客服管理后台的企业信息处查看
-	secret         string        // Secret是微信客服用于校验开发者身份的访问密钥，企业成功注册微信客服后，可在「微信客服管理后台-开发配置」处获取
-	token          string        // 用于生成签名校验回调请求的合法性
-	encodingAESKey string        // 回调消息加解密参数是AES密钥的Base64编码，用于解密回调消息内容对应的密文
+	corpID         string                  // 企业ID：企业开通的每个微信客服，都对应唯一的企业ID，企业可在微信客服管理后台的企业信息处查看
+	secret         string                  // Secret是微信客服用于校验开发者身份的访问密钥，企业成功注册微信客服后，可在「微信客服管理后台-开发配置」处获取
+	cryptoKeys     []CryptoKey             // 回调消息加解密配置，第一项为主配置
+	cryptList      []*crypto.WXBizMsgCrypt // 与cryptoKeys一一对应的加解密实例，可并发复用
+	cryptoMutex    sync.RWMutex
 	expireTime     time.Duration // 令牌过期时间
 	cache          cache.Cache
 	eventQueue     sync.Map //事件队列
 	mutex          sync.Mutex
-	accessToken    string // 用户访问凭证
-	isCloseCache   bool   // 是否自动缓存AccessToken, 默认缓存
+	accessToken    string         // 用户访问凭证
+	isCloseCache   bool           // 是否自动缓存AccessToken, 默认缓存
+	cursorStore    CursorStore    // 消息拉取游标存储
+	syncLocks      sync.Map       // 游标锁
+	dedup          *Deduplicator  // 消息去重
+	messageStore   msgstore.Store // 会话记录存储
+	isEnableQuota  bool           // 是否跟踪消息发送额度
+	quotaMutex     sync.Mutex
+	msgIDGenerator func(data []byte) string          // 消息ID生成函数
+	duplicateCodes []int64                           // msgid重复时接口返回的错误码
+	skipReplyState bool                              // 回复消息前是否跳过会话状态校验
+	syncListeners  []func(msgList []syncmsg.Message) // 拉取消息后的回调
+	listenerMutex  sync.RWMutex
 }
 
 // New 初始化微信客服实例
@@ -47,16 +72,34 @@ func New(options Options) (client *Client, err error) {
 		options.ExpireTime = 6000
 	}
 
+	if options.CursorStore == nil {
+		options.CursorStore = NewCacheCursorStore(options.Cache)
+	}
+
+	if options.MsgIDGenerator == nil {
+		options.MsgIDGenerator = randomMsgID
+	}
+
 	client = &Client{
 		corpID:         options.CorpID,
 		secret:         options.Secret,
-		token:          options.Token,
-		encodingAESKey: options.EncodingAESKey,
 		expireTime:     options.ExpireTime,
 		cache:          options.Cache,
 		eventQueue:     sync.Map{},
 		mutex:          sync.Mutex{},
 		isCloseCache:   options.IsCloseCache,
+		cursorStore:    options.CursorStore,
+		messageStore:   options.MessageStore,
+		isEnableQuota:  options.IsEnableQuota,
+		msgIDGenerator: options.MsgIDGenerator,
+		duplicateCodes: options.DuplicateMsgIDErrCodes,
+		skipReplyState: options.IsSkipReplyStateCheck,
+	}
+
+	client.SetCryptoKeys(CryptoKey{Token: options.Token, EncodingAESKey: options.EncodingAESKey}, options.PreviousKeys...)
+
+	if options.DedupExpireTime > 0 {
+		client.dedup = NewDeduplicator(options.Cache, "wechat:kf:msgid:"+options.CorpID+":", options.DedupExpireTime)
 	}
 
 	if options.Secret != "" {
@@ -0,0 +1,113 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsgonevent"
+)
+
+func TestSendMsgWithDefaultOptions(t *testing.T) {
+	var requests []map[string]interface{}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body := make(map[string]interface{})
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, body)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "errmsg": "ok", "msgid": body["msgid"]})
+	})
+	client := newTestClient(t, Options{})
+
+	info, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.MsgID) != msgIDMaxLength {
+		t.Fatalf("msgid = %q, want %d random characters", info.MsgID, msgIDMaxLength)
+	}
+	if len(requests) != 1 || requests[0]["msgid"] != info.MsgID {
+		t.Fatalf("requests = %v, want one request with msgid %s", requests, info.MsgID)
+	}
+
+	//相同msgid重试时不再请求接口
+	message := sendmsg.NewText("user", "kf", "hello")
+	message.MsgID = info.MsgID
+	if _, err = client.SendMsg(message); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want retry to be answered locally", len(requests))
+	}
+}
+
+func TestSendMsgReturnsMsgIDOnTransportError(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.Close()
+	})
+	client := newTestClient(t, Options{MsgIDGenerator: func([]byte) string { return "generated" }})
+
+	info, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello"))
+	if err == nil {
+		t.Fatal("SendMsg() err = nil, want transport error")
+	}
+	if info.MsgID != "generated" {
+		t.Fatalf("msgid = %q, want generated msgid for retry", info.MsgID)
+	}
+	onEvent, err := client.SendMsgOnEvent(sendmsgonevent.NewText("code", "hello"))
+	if err == nil {
+		t.Fatal("SendMsgOnEvent() err = nil, want transport error")
+	}
+	if onEvent.MsgID != "generated" {
+		t.Fatalf("msgid = %q, want generated msgid for retry", onEvent.MsgID)
+	}
+}
+
+func TestSendMsgDuplicateMsgID(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   []int64
+		errCode int64
+		wantErr bool
+	}{
+		{name: "configured code is success", codes: []int64{90001}, errCode: 90001},
+		{name: "other codes still fail", codes: []int64{90001}, errCode: 40058, wantErr: true},
+		{name: "no codes configured", errCode: 90001, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": tt.errCode, "errmsg": "duplicate"})
+			})
+			client := newTestClient(t, Options{DuplicateMsgIDErrCodes: tt.codes})
+
+			message := sendmsg.NewText("user", "kf", "hello")
+			message.MsgID = "retry"
+			info, err := client.SendMsg(message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendMsg() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if info.MsgID != "retry" {
+				t.Fatalf("msgid = %q, want retry", info.MsgID)
+			}
+			if tt.wantErr {
+				return
+			}
+			if info.ErrCode != 0 {
+				t.Fatalf("errcode = %d, want 0", info.ErrCode)
+			}
+			//已记录为发送成功，再次重试不再请求接口
+			if _, err = client.SendMsg(message); err != nil || requests != 1 {
+				t.Fatalf("retry err = %v, requests = %d, want answered locally", err, requests)
+			}
+		})
+	}
+}
@@ -13,22 +13,94 @@ type CryptoOptions struct {
 	EchoStr   string `form:"echostr"`
 }
 
+// CryptoKey 回调消息加解密配置
+type CryptoKey struct {
+	Token          string // 用于生成签名校验回调请求的合法性，历史配置为空时使用主配置的Token
+	EncodingAESKey string // 回调消息加解密参数是AES密钥的Base64编码，用于解密回调消息内容对应的密文
+}
+
+// SetCryptoKeys 更新回调消息加解密配置，primary为当前使用的配置，previous为轮换前仍可能收到回调的历史配置，解密时按顺序依次尝试
+func (r *Client) SetCryptoKeys(primary CryptoKey, previous ...CryptoKey) {
+	keys := make([]CryptoKey, 0, len(previous)+1)
+	keys = append(keys, primary)
+	for _, key := range previous {
+		//只轮换EncodingAESKey时历史配置可不填Token
+		if key.Token == "" {
+			key.Token = primary.Token
+		}
+		keys = append(keys, key)
+	}
+
+	cryptList := make([]*crypto.WXBizMsgCrypt, 0, len(keys))
+	for _, key := range keys {
+		cryptList = append(cryptList, crypto.NewWXBizMsgCrypt(key.Token, key.EncodingAESKey, r.corpID, crypto.XmlType))
+	}
+
+	r.cryptoMutex.Lock()
+	r.cryptoKeys = keys
+	r.cryptList = cryptList
+	r.cryptoMutex.Unlock()
+}
+
+// CryptoKeys 获取当前生效的回调消息加解密配置，第一项为主配置
+func (r *Client) CryptoKeys() []CryptoKey {
+	r.cryptoMutex.RLock()
+	defer r.cryptoMutex.RUnlock()
+	keys := make([]CryptoKey, len(r.cryptoKeys))
+	copy(keys, r.cryptoKeys)
+	return keys
+}
+
+func (r *Client) getCryptList() []*crypto.WXBizMsgCrypt {
+	r.cryptoMutex.RLock()
+	defer r.cryptoMutex.RUnlock()
+	return r.cryptList
+}
+
 // VerifyURL 验证请求参数是否合法
 func (r *Client) VerifyURL(options CryptoOptions) (string, error) {
-	wxCpt := crypto.NewWXBizMsgCrypt(r.token, r.encodingAESKey, r.corpID, crypto.XmlType)
-	data, err := wxCpt.VerifyURL(options.Signature, options.TimeStamp, options.Nonce, options.EchoStr)
-	if err != nil {
-		return "", errors.New(err.ErrMsg)
+	data, _, err := r.VerifyURLWithKey(options)
+	return data, err
+}
+
+// VerifyURLWithKey 验证请求参数是否合法，并返回匹配的配置序号，0为主配置
+func (r *Client) VerifyURLWithKey(options CryptoOptions) (string, int, error) {
+	var firstErr *crypto.CryptError
+	for index, wxCpt := range r.getCryptList() {
+		data, err := wxCpt.VerifyURL(options.Signature, options.TimeStamp, options.Nonce, options.EchoStr)
+		if err == nil {
+			return string(data), index, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
-	return string(data), nil
+	if firstErr == nil {
+		return "", -1, NewSDKErr(40015)
+	}
+	return "", -1, errors.New(firstErr.ErrMsg)
 }
 
 // DecryptMsg 解密消息
 func (r *Client) DecryptMsg(options CryptoOptions, postData []byte) ([]byte, error) {
-	wxCpt := crypto.NewWXBizMsgCrypt(r.token, r.encodingAESKey, r.corpID, crypto.XmlType)
-	message, status := wxCpt.DecryptMsg(options.Signature, options.TimeStamp, options.Nonce, postData)
-	if status != nil && status.ErrCode != 0 {
-		return nil, errors.New(status.ErrMsg)
+	message, _, err := r.DecryptMsgWithKey(options, postData)
+	return message, err
+}
+
+// DecryptMsgWithKey 解密消息，并返回匹配的配置序号，0为主配置
+func (r *Client) DecryptMsgWithKey(options CryptoOptions, postData []byte) ([]byte, int, error) {
+	var firstErr *crypto.CryptError
+	for index, wxCpt := range r.getCryptList() {
+		message, status := wxCpt.DecryptMsg(options.Signature, options.TimeStamp, options.Nonce, postData)
+		if status == nil || status.ErrCode == 0 {
+			return message, index, nil
+		}
+		if firstErr == nil {
+			firstErr = status
+		}
+	}
+	if firstErr == nil {
+		return nil, -1, NewSDKErr(40016)
 	}
-	return message, nil
+	return nil, -1, errors.New(firstErr.ErrMsg)
 }
@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/xml"
 	"fmt"
-	"math/rand"
+	"io"
+	"math/big"
 	"sort"
 	"strings"
 )
 
 const letterBytes = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
+// randReader 生成随机字符串使用的随机源
+var randReader io.Reader = rand.Reader
+
 const (
 	ValidateSignatureError int = -40001
 	ParseXmlError          int = -40002
@@ -75,11 +80,16 @@ type ProtocolProcessor interface {
 	serialize(msgSend *WXBizMsg4Send) ([]byte, *CryptError)
 }
 
+// WXBizMsgCrypt 回调消息加解密实例，初始化时预先解析AES密钥，可在多个协程中并发使用
 type WXBizMsgCrypt struct {
 	token             string
 	encodingAesKey    string
 	receiverId        string
 	protocolProcessor ProtocolProcessor
+	aesKey            []byte       // 解码后的AES密钥
+	block             cipher.Block // AES分组密码，只读状态，可并发使用
+	keyErr            *CryptError  // 密钥Base64解码错误，在加解密时返回
+	cipherErr         error        // 密钥长度不合法时创建AES实例的错误，加密时返回EncryptAESError，解密时返回DecryptAESError
 }
 
 type XmlProcessor struct {
@@ -109,15 +119,37 @@ func NewWXBizMsgCrypt(token, encodingAesKey, receiverId string, protocolType Pro
 	} else {
 		protocolProcessor = new(XmlProcessor)
 	}
-	return &WXBizMsgCrypt{token: token, encodingAesKey: encodingAesKey + "=", receiverId: receiverId, protocolProcessor: protocolProcessor}
+	wxCpt := &WXBizMsgCrypt{token: token, encodingAesKey: encodingAesKey + "=", receiverId: receiverId, protocolProcessor: protocolProcessor}
+	wxCpt.initCipher()
+	return wxCpt
+}
+
+func (r *WXBizMsgCrypt) initCipher() {
+	aesKey, err := base64.StdEncoding.DecodeString(r.encodingAesKey)
+	if nil != err {
+		r.keyErr = NewCryptError(DecodeBase64Error, err.Error())
+		return
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		r.cipherErr = err
+		return
+	}
+	r.aesKey = aesKey
+	r.block = block
 }
 
-func (r *WXBizMsgCrypt) randString(n int) string {
+func (r *WXBizMsgCrypt) randString(n int) (string, error) {
 	b := make([]byte, n)
+	max := big.NewInt(int64(len(letterBytes)))
 	for i := range b {
-		b[i] = letterBytes[rand.Int63()%int64(len(letterBytes))]
+		index, err := rand.Int(randReader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = letterBytes[index.Int64()]
 	}
-	return string(b)
+	return string(b), nil
 }
 
 func (r *WXBizMsgCrypt) pKCS7Padding(plaintext string, blockSize int) []byte {
@@ -137,27 +169,28 @@ func (r *WXBizMsgCrypt) pKCS7UnPadding(plaintext []byte, blockSize int) ([]byte,
 	if plaintextLen%blockSize != 0 {
 		return nil, NewCryptError(DecryptAESError, "pKCS7UnPadding text not a multiple of the block size")
 	}
+	//密钥不匹配时解密结果为随机数据，需要校验填充长度
 	paddingLen := int(plaintext[plaintextLen-1])
+	if paddingLen == 0 || paddingLen > blockSize || paddingLen > plaintextLen {
+		return nil, NewCryptError(DecryptAESError, "pKCS7UnPadding padding size is not valid")
+	}
 	return plaintext[:plaintextLen-paddingLen], nil
 }
 
 func (r *WXBizMsgCrypt) cbcEncryptor(plaintext string) ([]byte, *CryptError) {
-	aesKey, err := base64.StdEncoding.DecodeString(r.encodingAesKey)
-	if nil != err {
-		return nil, NewCryptError(DecodeBase64Error, err.Error())
+	if r.keyErr != nil {
+		return nil, r.keyErr
+	}
+	if r.cipherErr != nil {
+		return nil, NewCryptError(EncryptAESError, r.cipherErr.Error())
 	}
 	const blockSize = 32
 	padMsg := r.pKCS7Padding(plaintext, blockSize)
 
-	block, err := aes.NewCipher(aesKey)
-	if err != nil {
-		return nil, NewCryptError(EncryptAESError, err.Error())
-	}
-
 	ciphertext := make([]byte, len(padMsg))
-	iv := aesKey[:aes.BlockSize]
+	iv := r.aesKey[:aes.BlockSize]
 
-	mode := cipher.NewCBCEncrypter(block, iv)
+	mode := cipher.NewCBCEncrypter(r.block, iv)
 
 	mode.CryptBlocks(ciphertext, padMsg)
 	base64Msg := make([]byte, base64.StdEncoding.EncodedLen(len(ciphertext)))
@@ -167,9 +200,8 @@ func (r *WXBizMsgCrypt) cbcEncryptor(plaintext string) ([]byte, *CryptError) {
 }
 
 func (r *WXBizMsgCrypt) cbcDecipher(base64EncryptMsg string) ([]byte, *CryptError) {
-	aesKey, err := base64.StdEncoding.DecodeString(r.encodingAesKey)
-	if nil != err {
-		return nil, NewCryptError(DecodeBase64Error, err.Error())
+	if r.keyErr != nil {
+		return nil, r.keyErr
 	}
 
 	encryptMsg, err := base64.StdEncoding.DecodeString(base64EncryptMsg)
@@ -177,22 +209,21 @@ func (r *WXBizMsgCrypt) cbcDecipher(base64EncryptMsg string) ([]byte, *CryptErro
 		return nil, NewCryptError(DecodeBase64Error, err.Error())
 	}
 
-	block, err := aes.NewCipher(aesKey)
-	if err != nil {
-		return nil, NewCryptError(DecryptAESError, err.Error())
+	if r.cipherErr != nil {
+		return nil, NewCryptError(DecryptAESError, r.cipherErr.Error())
 	}
 
 	if len(encryptMsg) < aes.BlockSize {
 		return nil, NewCryptError(DecryptAESError, "encrypt_msg size is not valid")
 	}
 
-	iv := aesKey[:aes.BlockSize]
+	iv := r.aesKey[:aes.BlockSize]
 
 	if len(encryptMsg)%aes.BlockSize != 0 {
 		return nil, NewCryptError(DecryptAESError, "encrypt_msg not a multiple of the block size")
 	}
 
-	mode := cipher.NewCBCDecrypter(block, iv)
+	mode := cipher.NewCBCDecrypter(r.block, iv)
 
 	mode.CryptBlocks(encryptMsg, encryptMsg)
 
@@ -226,7 +257,7 @@ func (r *WXBizMsgCrypt) ParsePlainText(plaintext []byte) ([]byte, uint32, []byte
 	}
 	random := plaintext[:16]
 	msgLen := binary.BigEndian.Uint32(plaintext[16:20])
-	if textLen < (20 + msgLen) {
+	if textLen-20 < msgLen {
 		return nil, 0, nil, nil, NewCryptError(IllegalBuffer, "plain is to small 2")
 	}
 
@@ -262,7 +293,10 @@ func (r *WXBizMsgCrypt) VerifyURL(msgSignature, timestamp, nonce, echoStr string
 }
 
 func (r *WXBizMsgCrypt) EncryptMsg(replyMsg, timestamp, nonce string) ([]byte, *CryptError) {
-	randStr := r.randString(16)
+	randStr, randErr := r.randString(16)
+	if randErr != nil {
+		return nil, NewCryptError(EncryptAESError, randErr.Error())
+	}
 	var buffer bytes.Buffer
 	buffer.WriteString(randStr)
 
@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+const (
+	testToken          = "QDG6eK"
+	testEncodingAESKey = "jWmYm7qr5nMoAUwZRjGtBxmz3KA1tkAj3ykkR6q2B2C"
+	testReceiverID     = "wx5823bf96d3bd56c7"
+	testTimestamp      = "1409659813"
+	testNonce          = "1372623149"
+)
+
+// newTestCallback 生成加密后的回调请求内容和签名
+func newTestCallback(tb testing.TB) (signature string, postData []byte) {
+	wxCpt := NewWXBizMsgCrypt(testToken, testEncodingAESKey, testReceiverID, XmlType)
+	postData, cryptErr := wxCpt.EncryptMsg("<xml><Token><![CDATA[ENCApHxnGDNAVNY4AaSJKj4Tb5mwsEMzxhFmHVGcra996NR]]></Token></xml>", testTimestamp, testNonce)
+	if cryptErr != nil {
+		tb.Fatal(cryptErr.ErrMsg)
+	}
+	msg4Recv, cryptErr := wxCpt.protocolProcessor.parse(postData)
+	if cryptErr != nil {
+		tb.Fatal(cryptErr.ErrMsg)
+	}
+	// 回调请求中的密文字段与发送的一致，签名单独通过msg_signature参数传递
+	return wxCpt.calSignature(testTimestamp, testNonce, msg4Recv.Encrypt), postData
+}
+
+func TestDecryptMsg(t *testing.T) {
+	signature, postData := newTestCallback(t)
+	tests := []struct {
+		name      string
+		signature string
+		aesKey    string
+		wantCode  int
+	}{
+		{name: "valid", signature: signature, aesKey: testEncodingAESKey},
+		{name: "bad signature", signature: "invalid", aesKey: testEncodingAESKey, wantCode: ValidateSignatureError},
+		{name: "bad aes key", signature: signature, aesKey: "invalid", wantCode: DecryptAESError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wxCpt := NewWXBizMsgCrypt(testToken, tt.aesKey, testReceiverID, XmlType)
+			msg, cryptErr := wxCpt.DecryptMsg(tt.signature, testTimestamp, testNonce, postData)
+			if tt.wantCode != 0 {
+				if cryptErr == nil || cryptErr.ErrCode != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", cryptErr, tt.wantCode)
+				}
+				return
+			}
+			if cryptErr != nil {
+				t.Fatal(cryptErr.ErrMsg)
+			}
+			if len(msg) == 0 {
+				t.Fatal("empty message")
+			}
+		})
+	}
+}
+
+// errReader 模拟随机源读取失败
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("entropy unavailable")
+}
+
+func TestEncryptMsgErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		aesKey   string
+		failRand bool
+		wantCode int
+	}{
+		{name: "bad aes key", aesKey: "invalid", wantCode: EncryptAESError},
+		{name: "bad base64 key", aesKey: "!", wantCode: DecodeBase64Error},
+		{name: "random source fails", aesKey: testEncodingAESKey, failRand: true, wantCode: EncryptAESError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.failRand {
+				randReader = errReader{}
+				defer func() { randReader = rand.Reader }()
+			}
+			wxCpt := NewWXBizMsgCrypt(testToken, tt.aesKey, testReceiverID, XmlType)
+			if _, cryptErr := wxCpt.EncryptMsg("<xml></xml>", testTimestamp, testNonce); cryptErr == nil || cryptErr.ErrCode != tt.wantCode {
+				t.Fatalf("err = %v, want code %d", cryptErr, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestDecryptMsgWrongKey(t *testing.T) {
+	//签名相同但密钥不同时解密出随机数据，应返回错误而不是panic
+	wxCpt := NewWXBizMsgCrypt(testToken, "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8", testReceiverID, XmlType)
+	for i := 0; i < 100; i++ {
+		signature, postData := newTestCallback(t)
+		if _, cryptErr := wxCpt.DecryptMsg(signature, testTimestamp, testNonce, postData); cryptErr == nil {
+			t.Fatal("DecryptMsg() with wrong key succeeded")
+		}
+	}
+}
+
+func BenchmarkDecryptMsg(b *testing.B) {
+	signature, postData := newTestCallback(b)
+
+	b.Run("cached", func(b *testing.B) {
+		wxCpt := NewWXBizMsgCrypt(testToken, testEncodingAESKey, testReceiverID, XmlType)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, cryptErr := wxCpt.DecryptMsg(signature, testTimestamp, testNonce, postData); cryptErr != nil {
+				b.Fatal(cryptErr.ErrMsg)
+			}
+		}
+	})
+
+	b.Run("per-call", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wxCpt := NewWXBizMsgCrypt(testToken, testEncodingAESKey, testReceiverID, XmlType)
+			if _, cryptErr := wxCpt.DecryptMsg(signature, testTimestamp, testNonce, postData); cryptErr != nil {
+				b.Fatal(cryptErr.ErrMsg)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/crypto"
+)
+
+const (
+	testAESKeyCurrent  = "jWmYm7qr5nMoAUwZRjGtBxmz3KA1tkAj3ykkR6q2B2C"
+	testAESKeyPrevious = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8"
+	testAESKeyNext     = "ICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8"
+)
+
+// encryptCallback 使用指定配置模拟企业微信加密回调消息，返回回调参数和请求体
+func encryptCallback(t *testing.T, key CryptoKey, message string) (CryptoOptions, []byte) {
+	wxCpt := crypto.NewWXBizMsgCrypt(key.Token, key.EncodingAESKey, "corp", crypto.XmlType)
+	postData, cryptErr := wxCpt.EncryptMsg(message, "1409659813", "nonce")
+	if cryptErr != nil {
+		t.Fatal(cryptErr.ErrMsg)
+	}
+	var msg4Send crypto.WXBizMsg4Send
+	if err := xml.Unmarshal(postData, &msg4Send); err != nil {
+		t.Fatal(err)
+	}
+	options := CryptoOptions{
+		Signature: msg4Send.Signature.Value,
+		TimeStamp: "1409659813",
+		Nonce:     "nonce",
+	}
+	return options, postData
+}
+
+func TestDecryptMsgWithKey(t *testing.T) {
+	client := newTestClient(t, Options{
+		CorpID:         "corp",
+		Token:          "token",
+		EncodingAESKey: testAESKeyCurrent,
+		PreviousKeys: []CryptoKey{
+			{Token: "old-token", EncodingAESKey: testAESKeyPrevious},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		key       CryptoKey
+		wantIndex int
+		wantErr   bool
+	}{
+		{name: "primary", key: CryptoKey{Token: "token", EncodingAESKey: testAESKeyCurrent}, wantIndex: 0},
+		{name: "previous", key: CryptoKey{Token: "old-token", EncodingAESKey: testAESKeyPrevious}, wantIndex: 1},
+		{name: "unknown", key: CryptoKey{Token: "token", EncodingAESKey: testAESKeyNext}, wantIndex: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, postData := encryptCallback(t, tt.key, "<xml>hello</xml>")
+			message, index, err := client.DecryptMsgWithKey(options, postData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptMsgWithKey() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if index != tt.wantIndex {
+				t.Fatalf("index = %d, want %d", index, tt.wantIndex)
+			}
+			if !tt.wantErr && string(message) != "<xml>hello</xml>" {
+				t.Fatalf("message = %q", message)
+			}
+		})
+	}
+}
+
+func TestPreviousKeyDefaultsToPrimaryToken(t *testing.T) {
+	client := newTestClient(t, Options{
+		CorpID:         "corp",
+		Token:          "token",
+		EncodingAESKey: testAESKeyCurrent,
+		PreviousKeys:   []CryptoKey{{EncodingAESKey: testAESKeyPrevious}},
+	})
+	if keys := client.CryptoKeys(); keys[1].Token != "token" {
+		t.Fatalf("previous Token = %q, want token", keys[1].Token)
+	}
+
+	options, postData := encryptCallback(t, CryptoKey{Token: "token", EncodingAESKey: testAESKeyPrevious}, "<xml>hello</xml>")
+	if _, index, err := client.DecryptMsgWithKey(options, postData); err != nil || index != 1 {
+		t.Fatalf("DecryptMsgWithKey() index = %d, err = %v, want 1", index, err)
+	}
+}
+
+func TestSetCryptoKeysReload(t *testing.T) {
+	client := newTestClient(t, Options{
+		CorpID:         "corp",
+		Token:          "token",
+		EncodingAESKey: testAESKeyPrevious,
+	})
+	oldOptions, oldData := encryptCallback(t, CryptoKey{Token: "token", EncodingAESKey: testAESKeyPrevious}, "<xml>old</xml>")
+	newOptions, newData := encryptCallback(t, CryptoKey{Token: "token", EncodingAESKey: testAESKeyCurrent}, "<xml>new</xml>")
+	if _, _, err := client.DecryptMsgWithKey(newOptions, newData); err == nil {
+		t.Fatal("new key matched before reload")
+	}
+
+	//轮换后新配置生效，历史配置仍可解密
+	client.SetCryptoKeys(CryptoKey{Token: "token", EncodingAESKey: testAESKeyCurrent}, CryptoKey{EncodingAESKey: testAESKeyPrevious})
+	if _, index, err := client.DecryptMsgWithKey(newOptions, newData); err != nil || index != 0 {
+		t.Fatalf("new key index = %d, err = %v, want 0", index, err)
+	}
+	if _, index, err := client.DecryptMsgWithKey(oldOptions, oldData); err != nil || index != 1 {
+		t.Fatalf("old key index = %d, err = %v, want 1", index, err)
+	}
+
+	//移除历史配置后不再匹配
+	client.SetCryptoKeys(CryptoKey{Token: "token", EncodingAESKey: testAESKeyCurrent})
+	if _, index, err := client.DecryptMsgWithKey(oldOptions, oldData); err == nil || index != -1 {
+		t.Fatalf("old key index = %d, err = %v, want -1 and error", index, err)
+	}
+}
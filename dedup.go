@@ -0,0 +1,96 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/cache"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// DedupStats 消息去重统计
+type DedupStats struct {
+	Passed  uint64 // 通过的消息数
+	Dropped uint64 // 因重复被丢弃的消息数
+}
+
+// Deduplicator 根据msgid对拉取的消息去重，已处理的msgid记录在缓存中
+type Deduplicator struct {
+	cache      cache.Cache
+	prefix     string
+	expireTime time.Duration // 缓存有效期，单位为秒
+	passed     uint64
+	dropped    uint64
+}
+
+// NewDeduplicator 初始化消息去重实例，expireTime为msgid记录的有效期，如 24 * time.Hour，不足1秒时按1秒计算
+func NewDeduplicator(c cache.Cache, prefix string, expireTime time.Duration) *Deduplicator {
+	//缓存有效期以秒为单位
+	seconds := expireTime / time.Second
+	if seconds == 0 && expireTime > 0 {
+		seconds = 1
+	}
+	return &Deduplicator{
+		cache:      c,
+		prefix:     prefix,
+		expireTime: seconds,
+	}
+}
+
+// Filter 过滤已处理过的消息，同一批次内重复的消息也会被过滤
+func (r *Deduplicator) Filter(msgList []syncmsg.Message) ([]syncmsg.Message, error) {
+	result := make([]syncmsg.Message, 0, len(msgList))
+	seen := make(map[string]struct{}, len(msgList))
+	for _, msg := range msgList {
+		if msg.MsgID == "" {
+			result = append(result, msg)
+			continue
+		}
+		if _, ok := seen[msg.MsgID]; ok {
+			atomic.AddUint64(&r.dropped, 1)
+			continue
+		}
+		seen[msg.MsgID] = struct{}{}
+
+		val, err := r.cache.Get(r.prefix + msg.MsgID)
+		if err != nil {
+			return nil, NewSDKErr(50002)
+		}
+		if val != "" {
+			atomic.AddUint64(&r.dropped, 1)
+			continue
+		}
+		atomic.AddUint64(&r.passed, 1)
+		result = append(result, msg)
+	}
+	return result, nil
+}
+
+// Mark 记录已处理的消息
+func (r *Deduplicator) Mark(msgList []syncmsg.Message) error {
+	for _, msg := range msgList {
+		if msg.MsgID == "" {
+			continue
+		}
+		if err := r.cache.Set(r.prefix+msg.MsgID, "1", r.expireTime); err != nil {
+			return NewSDKErr(50002)
+		}
+	}
+	return nil
+}
+
+// Stats 获取去重统计
+func (r *Deduplicator) Stats() DedupStats {
+	return DedupStats{
+		Passed:  atomic.LoadUint64(&r.passed),
+		Dropped: atomic.LoadUint64(&r.dropped),
+	}
+}
+
+// DedupStats 获取拉取消息的去重统计，未开启去重时返回空统计
+func (r *Client) DedupStats() DedupStats {
+	if r.dedup == nil {
+		return DedupStats{}
+	}
+	return r.dedup.Stats()
+}
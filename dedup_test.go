@@ -0,0 +1,84 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func msgIDs(msgList []syncmsg.Message) []string {
+	ids := make([]string, 0, len(msgList))
+	for _, msg := range msgList {
+		ids = append(ids, msg.MsgID)
+	}
+	return ids
+}
+
+func TestDeduplicatorFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		marked  []string
+		input   []string
+		want    []string
+		dropped uint64
+	}{
+		{name: "new messages pass", input: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "marked messages are dropped", marked: []string{"a"}, input: []string{"a", "b"}, want: []string{"b"}, dropped: 1},
+		{name: "repeats within a page are dropped", input: []string{"a", "a", "b"}, want: []string{"a", "b"}, dropped: 1},
+		{name: "messages without msgid always pass", marked: []string{""}, input: []string{"", ""}, want: []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dedup := NewDeduplicator(newMemoryCache(), "test:", time.Hour)
+			var marked []syncmsg.Message
+			for _, id := range tt.marked {
+				marked = append(marked, syncmsg.Message{MsgID: id})
+			}
+			if err := dedup.Mark(marked); err != nil {
+				t.Fatal(err)
+			}
+			var input []syncmsg.Message
+			for _, id := range tt.input {
+				input = append(input, syncmsg.Message{MsgID: id})
+			}
+			result, err := dedup.Filter(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := msgIDs(result); len(got) != len(tt.want) {
+				t.Fatalf("Filter() = %v, want %v", got, tt.want)
+			} else {
+				for i := range got {
+					if got[i] != tt.want[i] {
+						t.Fatalf("Filter() = %v, want %v", got, tt.want)
+					}
+				}
+			}
+			if stats := dedup.Stats(); stats.Dropped != tt.dropped {
+				t.Fatalf("Dropped = %d, want %d", stats.Dropped, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestDeduplicatorExpireTimeInSeconds(t *testing.T) {
+	tests := []struct {
+		expireTime time.Duration
+		want       time.Duration
+	}{
+		{expireTime: 24 * time.Hour, want: 86400},
+		{expireTime: 90 * time.Second, want: 90},
+		{expireTime: time.Millisecond, want: 1},
+	}
+	for _, tt := range tests {
+		c := newMemoryCache()
+		dedup := NewDeduplicator(c, "test:", tt.expireTime)
+		if err := dedup.Mark([]syncmsg.Message{{MsgID: "a"}}); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.expires["test:a"]; got != tt.want {
+			t.Errorf("expire for %v = %d, want %d seconds", tt.expireTime, got, tt.want)
+		}
+	}
+}
@@ -15,6 +15,22 @@ const (
 	SDKCacheUnavailable Error = "缓存无效"
 	// SDKUnknownError 错误码：50003
 	SDKUnknownError Error = "未知错误"
+	// SDKReplyTargetMissing 错误码：50004
+	SDKReplyTargetMissing Error = "消息缺少客户UserID或客服帐号ID，无法回复"
+	// SDKServiceStateNotAllowed 错误码：50005
+	SDKServiceStateNotAllowed Error = "当前会话状态不允许通过API发送消息"
+	// SDKMessageStoreMissing 错误码：50006
+	SDKMessageStoreMissing Error = "未配置会话记录存储"
+	// SDKQuotaExceeded 错误码：50007
+	SDKQuotaExceeded Error = "客户48小时内可接收的消息已达上限或会话已过期"
+	// SDKUnsupportedMediaType 错误码：50008
+	SDKUnsupportedMediaType Error = "不支持的素材类型"
+	// SDKMediaSourceMissing 错误码：50009
+	SDKMediaSourceMissing Error = "未指定素材来源"
+	// SDKSentMsgNotFound 错误码：50010
+	SDKSentMsgNotFound Error = "未找到已发送消息的记录，请指定客服帐号ID"
+	// SDKSyncHandlerMissing 错误码：50011
+	SDKSyncHandlerMissing Error = "未指定消息处理函数"
 	// SDKInvalidCredential 错误码：40001
 	SDKInvalidCredential Error = "不合法的secret参数"
 	// SDKInvalidImageSize 错误码：40009
@@ -60,6 +76,14 @@ var codeDic = map[int64]error{
 	50001: SDKInitFailed,
 	50002: SDKCacheUnavailable,
 	50003: SDKUnknownError,
+	50004: SDKReplyTargetMissing,
+	50005: SDKServiceStateNotAllowed,
+	50006: SDKMessageStoreMissing,
+	50007: SDKQuotaExceeded,
+	50008: SDKUnsupportedMediaType,
+	50009: SDKMediaSourceMissing,
+	50010: SDKSentMsgNotFound,
+	50011: SDKSyncHandlerMissing,
 	40001: SDKInvalidCredential,
 	40009: SDKInvalidImageSize,
 	40013: SDKInvalidCorpID,
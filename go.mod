@@ -2,4 +2,7 @@ module github.com/NICEXAI/WeChatCustomerServiceSDK
 
 go 1.16
 
-require github.com/go-redis/redis/v8 v8.11.0
+require (
+	github.com/go-redis/redis/v8 v8.11.0
+	gopkg.in/yaml.v2 v2.3.0
+)
@@ -0,0 +1,88 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCache 测试用内存缓存，记录每个键的有效期
+type memoryCache struct {
+	mutex   sync.Mutex
+	data    map[string]string
+	expires map[string]time.Duration
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{
+		data:    make(map[string]string),
+		expires: make(map[string]time.Duration),
+	}
+}
+
+func (r *memoryCache) Set(k, v string, expires time.Duration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.data[k] = v
+	r.expires[k] = expires
+	return nil
+}
+
+func (r *memoryCache) Get(k string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.data[k], nil
+}
+
+// rewriteTransport 将请求转发到测试服务
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (r rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	return r.next.RoundTrip(req)
+}
+
+// newTestServer 启动模拟接口服务，测试期间发往企业微信接口的请求都由 handler 处理
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	target, _ := url.Parse(server.URL)
+	origin := http.DefaultTransport
+	http.DefaultTransport = rewriteTransport{target: target, next: server.Client().Transport}
+	t.Cleanup(func() {
+		http.DefaultTransport = origin
+		server.Close()
+	})
+	return server
+}
+
+// newTestClient 使用默认参数初始化不获取AccessToken的测试实例
+func newTestClient(t *testing.T, options Options) *Client {
+	if options.Cache == nil {
+		options.Cache = newMemoryCache()
+	}
+	client, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// setQuota 直接写入客户的额度记录，startAt 为客户最近一次发送消息的时间
+func setQuota(t *testing.T, client *Client, openKFID, externalUserID string, startAt time.Time, sentCount int) {
+	data, err := json.Marshal(quotaRecord{StartAt: startAt.Unix(), SentCount: sentCount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.cache.Set(client.quotaKey(openKFID, externalUserID), string(data), 0); err != nil {
+		t.Fatal(err)
+	}
+}
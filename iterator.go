@@ -0,0 +1,169 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// syncPage 已拉取的一页消息
+type syncPage struct {
+	msgList    []syncmsg.Message
+	nextCursor string
+	err        error
+}
+
+// MsgIterator 消息迭代器，在后台按需拉取后续分页，最多预取一页，消费完一页后才保存该页游标并触发会话记录保存和拉取回调
+// 迭代器在迭代结束、出错或关闭前持有游标锁，同一游标的 SyncAll、SyncWorkerPool 和 Poller 会等待锁释放后再拉取
+type MsgIterator struct {
+	client  *Client
+	key     string
+	lock    *sync.Mutex
+	unlock  sync.Once
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pages   chan syncPage
+	wg      sync.WaitGroup
+	page    syncPage
+	index   int
+	current syncmsg.Message
+	err     error
+	done    bool
+}
+
+// SyncIterator 创建消息迭代器，options.Cursor 为空时从已保存的游标开始拉取
+// 同一游标正在被拉取时会等待其完成，Next 返回false时自动释放游标锁，提前退出循环时必须调用 Close 释放游标锁
+//
+//	iter := client.SyncIterator(ctx, options)
+//	defer iter.Close()
+//	for iter.Next() {
+//		msg := iter.Message()
+//	}
+//	if err := iter.Err(); err != nil {}
+func (r *Client) SyncIterator(ctx context.Context, options SyncMsgOptions) *MsgIterator {
+	key := r.cursorKey(options.OpenKFID)
+	lock := r.syncLock(key)
+	lock.Lock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	iter := &MsgIterator{
+		client: r,
+		key:    key,
+		lock:   lock,
+		ctx:    ctx,
+		cancel: cancel,
+		pages:  make(chan syncPage, 1),
+	}
+
+	iter.wg.Add(1)
+	go iter.fetch(options)
+	return iter
+}
+
+// fetch 循环拉取分页，通道已满时阻塞等待消费
+func (r *MsgIterator) fetch(options SyncMsgOptions) {
+	defer r.wg.Done()
+	defer close(r.pages)
+
+	cursor, err := r.client.loadCursor(r.key, options.Cursor)
+	if err != nil {
+		r.send(syncPage{err: err})
+		return
+	}
+	options.Cursor = cursor
+
+	for {
+		if r.ctx.Err() != nil {
+			return
+		}
+		info, err := r.client.fetchMsg(options)
+		if err != nil {
+			r.send(syncPage{err: err})
+			return
+		}
+		if !r.send(syncPage{msgList: info.MsgList, nextCursor: info.NextCursor}) {
+			return
+		}
+		if info.HasMore != 1 || info.NextCursor == "" {
+			return
+		}
+		options.Cursor = info.NextCursor
+	}
+}
+
+func (r *MsgIterator) send(page syncPage) bool {
+	select {
+	case r.pages <- page:
+		return true
+	case <-r.ctx.Done():
+		return false
+	}
+}
+
+// Next 移动到下一条消息，没有更多消息或出错时返回false
+func (r *MsgIterator) Next() bool {
+	if r.done {
+		return false
+	}
+	for r.index >= len(r.page.msgList) {
+		//当前页已消费完毕，保存游标后读取下一页
+		if err := r.client.commitMsgList(r.key, r.page.msgList, r.page.nextCursor); err != nil {
+			return r.stop(err)
+		}
+		r.page = syncPage{}
+		r.index = 0
+
+		select {
+		case page, ok := <-r.pages:
+			if !ok {
+				return r.stop(r.ctx.Err())
+			}
+			if page.err != nil {
+				return r.stop(page.err)
+			}
+			r.page = page
+		case <-r.ctx.Done():
+			return r.stop(r.ctx.Err())
+		}
+	}
+	r.current = r.page.msgList[r.index]
+	r.index++
+	return true
+}
+
+// stop 结束迭代并释放游标锁
+func (r *MsgIterator) stop(err error) bool {
+	r.err = err
+	r.release()
+	return false
+}
+
+// release 停止后台拉取并释放游标锁，可重复调用
+func (r *MsgIterator) release() {
+	r.cancel()
+	for range r.pages {
+	}
+	r.wg.Wait()
+	r.done = true
+	r.unlock.Do(r.lock.Unlock)
+}
+
+// Message 当前消息
+func (r *MsgIterator) Message() syncmsg.Message {
+	return r.current
+}
+
+// Err 迭代过程中遇到的错误，正常结束或被取消时返回nil
+func (r *MsgIterator) Err() error {
+	if r.err == context.Canceled {
+		return nil
+	}
+	return r.err
+}
+
+// Close 停止拉取并释放游标锁，未消费完的分页不会保存游标，可重复调用
+func (r *MsgIterator) Close() error {
+	r.release()
+	return nil
+}
@@ -0,0 +1,105 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func TestSyncIterator(t *testing.T) {
+	newSyncServer(t,
+		syncPageFixture{NextCursor: "c1", HasMore: 1, MsgIDs: []string{"a", "b"}},
+		syncPageFixture{Cursor: "c1", NextCursor: "c2", MsgIDs: []string{"c"}},
+	)
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	iter := client.SyncIterator(context.Background(), SyncMsgOptions{})
+	var got []string
+	for iter.Next() {
+		got = append(got, iter.Message().MsgID)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatal(err)
+	}
+	_ = iter.Close()
+
+	if len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Fatalf("messages = %v, want [a b c]", got)
+	}
+	if cursor, _ := client.cursorStore.GetCursor(client.cursorKey("")); cursor != "c2" {
+		t.Fatalf("cursor = %q, want c2", cursor)
+	}
+}
+
+func TestSyncIteratorHoldsCursorLock(t *testing.T) {
+	newSyncServer(t,
+		syncPageFixture{NextCursor: "c1", MsgIDs: []string{"a"}},
+		syncPageFixture{Cursor: "c1", NextCursor: "c1"},
+	)
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	iter := client.SyncIterator(context.Background(), SyncMsgOptions{})
+	if !iter.Next() {
+		t.Fatalf("Next() = false, err = %v", iter.Err())
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.SyncAll(SyncMsgOptions{}, func(msgList []syncmsg.Message) error { return nil })
+	}()
+	select {
+	case <-done:
+		t.Fatal("SyncAll ran while the iterator was open")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_ = iter.Close()
+	_ = iter.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SyncAll did not run after Close")
+	}
+}
+
+func TestSyncIteratorReleasesLockWhenFinished(t *testing.T) {
+	tests := []struct {
+		name    string
+		pages   []syncPageFixture
+		wantErr bool
+	}{
+		{name: "finished", pages: []syncPageFixture{{NextCursor: "c1", MsgIDs: []string{"a"}}, {Cursor: "c1", NextCursor: "c1"}}},
+		{name: "failed", pages: []syncPageFixture{{Cursor: "unknown"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newSyncServer(t, tt.pages...)
+			client := newTestClient(t, Options{CorpID: "corp"})
+
+			iter := client.SyncIterator(context.Background(), SyncMsgOptions{})
+			for iter.Next() {
+			}
+			if err := iter.Err(); (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			//未调用 Close 时同一游标也可以继续拉取
+			done := make(chan struct{})
+			go func() {
+				_ = client.SyncAll(SyncMsgOptions{}, func(msgList []syncmsg.Message) error { return nil })
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("cursor lock was not released when iteration ended")
+			}
+			_ = iter.Close()
+		})
+	}
+}
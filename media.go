@@ -81,6 +81,7 @@ func (r *Client) MediaOriginUpload(fileName, fileType string, size int, body []b
 }
 
 // MediaGet 获取临时素材
+// 返回的链接中包含access_token，如需在服务端下载素材请使用 MediaDownload
 func (r *Client) MediaGet(mediaID string) string {
 	return fmt.Sprintf(mediaGetAddr, r.accessToken, mediaID)
 }
@@ -0,0 +1,100 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/util"
+)
+
+// 错误响应内容的最大读取长度
+const mediaErrorBodyLimit = 4096
+
+// MediaDownloadOptions 下载临时素材请求参数
+type MediaDownloadOptions struct {
+	MediaID string // 媒体文件ID
+	Offset  int64  // 开始下载的字节位置，用于断点续传或分段下载
+	Length  int64  // 下载的字节数，为0时下载至文件末尾
+}
+
+// MediaDownloadSchema 下载临时素材响应内容
+type MediaDownloadSchema struct {
+	FileName      string // 文件名，取自Content-Disposition
+	ContentType   string // 文件类型
+	ContentLength int64  // 本次响应的内容长度，未知时为-1
+	ContentRange  string // 分段下载时返回的内容范围，如：bytes 0-1023/4096
+	Written       int64  // 已写入的字节数
+}
+
+// MediaDownload 下载临时素材并写入 w，下载链接中的access_token不会暴露给调用方
+// 素材不存在或已过期时返回对应的错误，access_token失效时会刷新后重试一次
+func (r *Client) MediaDownload(w io.Writer, options MediaDownloadOptions) (info MediaDownloadSchema, err error) {
+	info, err = r.mediaDownload(w, options)
+	if err == SDKAccessTokenInvalid || err == SDKAccessTokenExpired {
+		if err = r.RefreshAccessToken(); err != nil {
+			return info, err
+		}
+		return r.mediaDownload(w, options)
+	}
+	return info, err
+}
+
+func (r *Client) mediaDownload(w io.Writer, options MediaDownloadOptions) (info MediaDownloadSchema, err error) {
+	header := http.Header{}
+	if options.Offset > 0 || options.Length > 0 {
+		rangeEnd := ""
+		if options.Length > 0 {
+			rangeEnd = strconv.FormatInt(options.Offset+options.Length-1, 10)
+		}
+		header.Set("Range", fmt.Sprintf("bytes=%d-%s", options.Offset, rangeEnd))
+	}
+	resp, err := util.HttpGetStream(fmt.Sprintf(mediaGetAddr, r.accessToken, url.QueryEscape(options.MediaID)), header)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	info.ContentType = resp.Header.Get("Content-Type")
+	info.ContentLength = resp.ContentLength
+	info.ContentRange = resp.Header.Get("Content-Range")
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		info.FileName = strings.Trim(params["filename"], `"`)
+	}
+
+	//素材获取失败时返回JSON格式的错误信息
+	if isMediaErrorBody(info.ContentType) {
+		data, err := ioutil.ReadAll(io.LimitReader(resp.Body, mediaErrorBodyLimit))
+		if err != nil {
+			return info, err
+		}
+		var result BaseModel
+		if err = json.Unmarshal(data, &result); err == nil && result.ErrCode != 0 {
+			return info, NewSDKErr(result.ErrCode, result.ErrMsg)
+		}
+		written, err := w.Write(data)
+		info.Written = int64(written)
+		if err != nil {
+			return info, err
+		}
+	}
+
+	written, err := io.Copy(w, resp.Body)
+	info.Written += written
+	return info, err
+}
+
+// isMediaErrorBody 判断响应内容是否可能为错误信息
+func isMediaErrorBody(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/plain"
+}
@@ -0,0 +1,150 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// downloadServer 模拟获取调用凭证和下载临时素材接口，记录每次下载请求的access_token和Range
+type downloadServer struct {
+	mutex       sync.Mutex
+	validToken  string // 可以正常下载的access_token
+	issuedToken string // 刷新时下发的access_token
+	tokens      []string
+	ranges      []string
+	refreshes   int
+}
+
+func newDownloadServer(t *testing.T, validToken, issuedToken string) *downloadServer {
+	server := &downloadServer{validToken: validToken, issuedToken: issuedToken}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if strings.HasSuffix(req.URL.Path, "/gettoken") {
+			server.refreshes++
+			_, _ = w.Write([]byte(`{"errcode":0,"access_token":"` + server.issuedToken + `","expires_in":7200}`))
+			return
+		}
+		token := req.URL.Query().Get("access_token")
+		server.tokens = append(server.tokens, token)
+		server.ranges = append(server.ranges, req.Header.Get("Range"))
+		switch {
+		case token != server.validToken:
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+		case req.URL.Query().Get("media_id") == "missing":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"errcode":40007,"errmsg":"invalid media_id"}`))
+		case req.Header.Get("Range") != "":
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Range", "bytes 2-5/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte("2345"))
+		default:
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''%E5%9B%BE%E7%89%87.png`)
+			_, _ = w.Write([]byte("0123456789"))
+		}
+	})
+	return server
+}
+
+func TestMediaDownload(t *testing.T) {
+	newDownloadServer(t, "", "")
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	var buf bytes.Buffer
+	info, err := client.MediaDownload(&buf, MediaDownloadOptions{MediaID: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "0123456789" || info.Written != 10 {
+		t.Fatalf("written = %d %q, want 0123456789", info.Written, buf.String())
+	}
+	if info.FileName != "图片.png" || info.ContentType != "image/png" {
+		t.Fatalf("FileName = %q, ContentType = %q", info.FileName, info.ContentType)
+	}
+}
+
+func TestMediaDownloadRange(t *testing.T) {
+	server := newDownloadServer(t, "", "")
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	tests := []struct {
+		name      string
+		options   MediaDownloadOptions
+		wantRange string
+	}{
+		{name: "offset and length", options: MediaDownloadOptions{MediaID: "media", Offset: 2, Length: 4}, wantRange: "bytes=2-5"},
+		{name: "offset only", options: MediaDownloadOptions{MediaID: "media", Offset: 2}, wantRange: "bytes=2-"},
+		{name: "length only", options: MediaDownloadOptions{MediaID: "media", Length: 4}, wantRange: "bytes=0-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			info, err := client.MediaDownload(&buf, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := server.ranges[len(server.ranges)-1]; got != tt.wantRange {
+				t.Fatalf("Range = %q, want %q", got, tt.wantRange)
+			}
+			if info.ContentRange != "bytes 2-5/10" || buf.String() != "2345" {
+				t.Fatalf("ContentRange = %q, body = %q", info.ContentRange, buf.String())
+			}
+		})
+	}
+}
+
+func TestMediaDownloadErrorBody(t *testing.T) {
+	newDownloadServer(t, "", "")
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	//接口返回200状态码和JSON错误信息时不写入 w
+	var buf bytes.Buffer
+	_, err := client.MediaDownload(&buf, MediaDownloadOptions{MediaID: "missing"})
+	if err == nil || err.Error() != "invalid media_id" {
+		t.Fatalf("err = %v, want invalid media_id", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("written %q on error", buf.String())
+	}
+}
+
+func TestMediaDownloadRefreshesToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		issuedToken   string
+		wantErr       error
+		wantTokens    []string
+		wantRefreshes int
+	}{
+		{name: "retry succeeds", issuedToken: "fresh", wantTokens: []string{"stale", "fresh"}, wantRefreshes: 1},
+		{name: "retry once", issuedToken: "still-stale", wantErr: SDKAccessTokenExpired, wantTokens: []string{"stale", "still-stale"}, wantRefreshes: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDownloadServer(t, "fresh", tt.issuedToken)
+			client := newTestClient(t, Options{CorpID: "corp"})
+			client.accessToken = "stale"
+
+			var buf bytes.Buffer
+			_, err := client.MediaDownload(&buf, MediaDownloadOptions{MediaID: "media"})
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !equalStrings(server.tokens, tt.wantTokens) {
+				t.Fatalf("tokens = %v, want %v", server.tokens, tt.wantTokens)
+			}
+			if server.refreshes != tt.wantRefreshes {
+				t.Fatalf("refreshes = %d, want %d", server.refreshes, tt.wantRefreshes)
+			}
+			if tt.wantErr == nil && buf.String() != "0123456789" {
+				t.Fatalf("body = %q", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+)
+
+// msgID可用字符，取值范围：[0-9a-zA-Z_-]
+const msgIDLetters = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// msgID最大长度
+const msgIDMaxLength = 32
+
+// 已发送消息ID的记录有效期，单位为秒
+const sentMsgIDExpireTime = 48 * 3600
+
+// RandomMsgID 生成32位随机消息ID，为 Options.MsgIDGenerator 的默认值
+func RandomMsgID() string {
+	return randomMsgID(nil)
+}
+
+func randomMsgID(_ []byte) string {
+	b := make([]byte, msgIDMaxLength)
+	max := big.NewInt(int64(len(msgIDLetters)))
+	for i := range b {
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		b[i] = msgIDLetters[index.Int64()]
+	}
+	return string(b)
+}
+
+// HashMsgID 根据请求内容生成确定的消息ID，相同内容重复发送时使用相同的msgid，可作为 Options.MsgIDGenerator 使用
+// 注意：在已发送消息ID的记录有效期内，向同一客户重复发送相同内容会被视为重复请求
+func HashMsgID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:msgIDMaxLength]
+}
+
+// sentMsgRecord 已发送消息ID的缓存记录
+type sentMsgRecord struct {
+	OpenKFID       string `json:"open_kfid"`                 // 发送消息的客服帐号ID
+	ExternalUserID string `json:"external_userid,omitempty"` // 接收消息的客户UserID
+}
+
+// ensureMsgID 未指定msgid时自动生成
+func (r *Client) ensureMsgID(payload sendPayload) (sendPayload, error) {
+	if payload.MsgID != "" {
+		return payload, nil
+	}
+	return payload.withMsgID(r.msgIDGenerator(payload.data))
+}
+
+// isMsgIDSent 判断消息ID是否已发送成功
+func (r *Client) isMsgIDSent(msgID string) (bool, error) {
+	data, err := r.cache.Get(r.sentMsgIDKey(msgID))
+	if err != nil {
+		return false, NewSDKErr(50002)
+	}
+	return data != "", nil
+}
+
+// markMsgIDSent 记录已发送成功的消息ID
+func (r *Client) markMsgIDSent(payload sendPayload) error {
+	data, err := json.Marshal(sentMsgRecord{OpenKFID: payload.OpenKFID, ExternalUserID: payload.ToUser})
+	if err != nil {
+		return err
+	}
+	if err = r.cache.Set(r.sentMsgIDKey(payload.MsgID), string(data), sentMsgIDExpireTime); err != nil {
+		return NewSDKErr(50002)
+	}
+	return nil
+}
+
+func (r *Client) sentMsgIDKey(msgID string) string {
+	return "wechat:kf:sent:" + r.corpID + ":" + msgID
+}
+
+// isDuplicateMsgID 判断接口是否因msgid重复而拒绝请求，此时消息已在之前的请求中发送成功
+func (r *Client) isDuplicateMsgID(errCode int64) bool {
+	for _, code := range r.duplicateCodes {
+		if errCode != 0 && code == errCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgstore"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// saveSyncedMessages 保存拉取到的消息和事件
+func (r *Client) saveSyncedMessages(msgList []syncmsg.Message) error {
+	if r.messageStore == nil || len(msgList) == 0 {
+		return nil
+	}
+	records := make([]msgstore.Record, 0, len(msgList))
+	for _, msg := range msgList {
+		records = append(records, msgstore.Record{
+			MsgID:          msg.MsgID,
+			Direction:      msgstore.DirectionIn,
+			OpenKFID:       msg.OpenKFID,
+			ExternalUserID: msg.ExternalUserID,
+			MsgType:        msg.MsgType,
+			EventType:      msg.EventType,
+			SendTime:       msg.SendTime,
+			Data:           msg.OriginData,
+		})
+	}
+	if err := r.messageStore.Save(records...); err != nil {
+		return err
+	}
+	return r.saveEventCodes(msgList)
+}
+
+// saveSentMessage 保存发送成功的消息
+func (r *Client) saveSentMessage(payload sendPayload, msgID string) error {
+	if r.messageStore == nil {
+		return nil
+	}
+	//事件响应消息只携带code，根据拉取到的事件或变更会话状态的结果补全会话
+	if payload.Code != "" && payload.OpenKFID == "" {
+		recipient, err := r.getEventCode(payload.Code)
+		if err != nil {
+			return err
+		}
+		payload.OpenKFID, payload.ToUser = recipient.OpenKFID, recipient.ExternalUserID
+	}
+	return r.messageStore.Save(msgstore.Record{
+		MsgID:          msgID,
+		Direction:      msgstore.DirectionOut,
+		OpenKFID:       payload.OpenKFID,
+		ExternalUserID: payload.ToUser,
+		MsgType:        payload.MsgType,
+		SendTime:       uint64(time.Now().Unix()),
+		Data:           payload.data,
+	})
+}
+
+// 事件响应code对应会话的记录有效期，单位为秒，与code的最长有效期一致
+const eventCodeExpireTime = 48 * 3600
+
+// eventCodeRecipient 事件响应code对应的会话
+type eventCodeRecipient struct {
+	OpenKFID       string `json:"open_kfid"`       // 客服帐号ID
+	ExternalUserID string `json:"external_userid"` // 客户UserID
+}
+
+// saveEventCodes 记录拉取到的事件中可用于发送事件响应消息的code
+func (r *Client) saveEventCodes(msgList []syncmsg.Message) error {
+	for _, msg := range msgList {
+		code := ""
+		switch msg.EventType {
+		case syncmsg.EventTypeEnterSession:
+			event, err := msg.GetEnterSessionEvent()
+			if err != nil {
+				return err
+			}
+			code = event.Event.WelcomeCode
+		case syncmsg.EventTypeSessionStatusChange:
+			event, err := msg.GetSessionStatusChangeEvent()
+			if err != nil {
+				return err
+			}
+			code = event.Event.MsgCode
+		}
+		if err := r.saveEventCode(code, msg.OpenKFID, msg.ExternalUserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveEventCode 记录事件响应code对应的会话，未配置会话记录存储时不记录
+func (r *Client) saveEventCode(code, openKFID, externalUserID string) error {
+	if r.messageStore == nil || code == "" {
+		return nil
+	}
+	data, err := json.Marshal(eventCodeRecipient{OpenKFID: openKFID, ExternalUserID: externalUserID})
+	if err != nil {
+		return err
+	}
+	if err = r.cache.Set(r.eventCodeKey(code), string(data), eventCodeExpireTime); err != nil {
+		return NewSDKErr(50002)
+	}
+	return nil
+}
+
+// getEventCode 获取事件响应code对应的会话，无记录时返回空值
+func (r *Client) getEventCode(code string) (recipient eventCodeRecipient, err error) {
+	data, err := r.cache.Get(r.eventCodeKey(code))
+	if err != nil {
+		return recipient, NewSDKErr(50002)
+	}
+	if data == "" {
+		return recipient, nil
+	}
+	err = json.Unmarshal([]byte(data), &recipient)
+	return recipient, err
+}
+
+func (r *Client) eventCodeKey(code string) string {
+	return "wechat:kf:code:" + r.corpID + ":" + code
+}
+
+// QueryMessages 查询会话记录，需在初始化时配置 MessageStore
+func (r *Client) QueryMessages(query msgstore.Query) ([]msgstore.Record, error) {
+	if r.messageStore == nil {
+		return nil, NewSDKErr(50006)
+	}
+	return r.messageStore.Query(query)
+}
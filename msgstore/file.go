@@ -0,0 +1,136 @@
+package msgstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// fileIndex 会话记录在文件中的位置
+type fileIndex struct {
+	openKFID       string
+	externalUserID string
+	sendTime       uint64
+	offset         int64
+	length         int
+}
+
+// File 基于文件的会话记录存储，每条记录以一行JSON追加写入，打开时扫描文件在内存中建立索引
+type File struct {
+	mutex  sync.RWMutex
+	file   *os.File
+	size   int64
+	index  []fileIndex
+	msgIDs map[string]struct{}
+}
+
+// NewFile 打开或创建会话记录文件
+func NewFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	store := &File{
+		file:   file,
+		msgIDs: make(map[string]struct{}),
+	}
+	if err = store.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// load 扫描文件建立索引，末尾不完整的记录会被截断
+func (r *File) load() error {
+	reader := bufio.NewReader(r.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		record := Record{}
+		if err = json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		r.addIndex(record, offset, len(line))
+		offset += int64(len(line))
+	}
+	if err := r.file.Truncate(offset); err != nil {
+		return err
+	}
+	r.size = offset
+	return nil
+}
+
+func (r *File) addIndex(record Record, offset int64, length int) {
+	if record.MsgID != "" {
+		r.msgIDs[record.MsgID] = struct{}{}
+	}
+	r.index = append(r.index, fileIndex{
+		openKFID:       record.OpenKFID,
+		externalUserID: record.ExternalUserID,
+		sendTime:       record.SendTime,
+		offset:         offset,
+		length:         length,
+	})
+}
+
+// Save 追加保存会话记录
+func (r *File) Save(records ...Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, record := range records {
+		if record.MsgID != "" {
+			if _, ok := r.msgIDs[record.MsgID]; ok {
+				continue
+			}
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if _, err = r.file.WriteAt(line, r.size); err != nil {
+			return err
+		}
+		r.addIndex(record, r.size, len(line))
+		r.size += int64(len(line))
+	}
+	return r.file.Sync()
+}
+
+// Query 查询会话记录
+func (r *File) Query(query Query) ([]Record, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	result := make([]Record, 0)
+	for _, item := range r.index {
+		if !query.match(item.openKFID, item.externalUserID, item.sendTime) {
+			continue
+		}
+		line := make([]byte, item.length)
+		if _, err := r.file.ReadAt(line, item.offset); err != nil {
+			return nil, err
+		}
+		record := Record{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, err
+		}
+		result = append(result, record)
+	}
+	return sortRecords(result, query.Limit), nil
+}
+
+// Close 关闭会话记录文件
+func (r *File) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,46 @@
+package msgstore
+
+import "sync"
+
+// Memory 内存会话记录存储，进程退出后数据丢失
+type Memory struct {
+	mutex   sync.RWMutex
+	records []Record
+	msgIDs  map[string]struct{}
+}
+
+// NewMemory 初始化内存会话记录存储
+func NewMemory() *Memory {
+	return &Memory{
+		msgIDs: make(map[string]struct{}),
+	}
+}
+
+// Save 保存会话记录
+func (r *Memory) Save(records ...Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, record := range records {
+		if record.MsgID != "" {
+			if _, ok := r.msgIDs[record.MsgID]; ok {
+				continue
+			}
+			r.msgIDs[record.MsgID] = struct{}{}
+		}
+		r.records = append(r.records, record)
+	}
+	return nil
+}
+
+// Query 查询会话记录
+func (r *Memory) Query(query Query) ([]Record, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	result := make([]Record, 0)
+	for _, record := range r.records {
+		if query.match(record.OpenKFID, record.ExternalUserID, record.SendTime) {
+			result = append(result, record)
+		}
+	}
+	return sortRecords(result, query.Limit), nil
+}
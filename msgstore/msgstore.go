@@ -0,0 +1,72 @@
+package msgstore
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Direction 消息方向
+type Direction string
+
+const (
+	// DirectionIn 通过拉取消息接口获取的消息和事件
+	DirectionIn Direction = "in"
+	// DirectionOut 通过发送消息接口发出的消息
+	DirectionOut Direction = "out"
+)
+
+// Record 会话记录
+type Record struct {
+	MsgID          string          `json:"msgid"`                // 消息ID
+	Direction      Direction       `json:"direction"`            // 消息方向
+	OpenKFID       string          `json:"open_kfid"`            // 客服帐号ID
+	ExternalUserID string          `json:"external_userid"`      // 客户UserID，发送事件响应消息时根据code对应的事件补全
+	MsgType        string          `json:"msgtype"`              // 消息类型
+	EventType      string          `json:"event_type,omitempty"` // 事件类型
+	SendTime       uint64          `json:"send_time"`            // 消息发送时间，unix时间戳
+	Data           json.RawMessage `json:"data"`                 // 原始消息内容
+}
+
+// Query 会话记录查询条件，字段为空时不限制
+type Query struct {
+	OpenKFID       string    // 客服帐号ID
+	ExternalUserID string    // 客户UserID
+	StartTime      time.Time // 开始时间，包含
+	EndTime        time.Time // 结束时间，不包含
+	Limit          int       // 最多返回的记录数，返回时间最早的记录
+}
+
+// Store 会话记录存储，相同msgid的记录只保存一次
+type Store interface {
+	Save(records ...Record) error
+	Query(query Query) ([]Record, error)
+}
+
+// match 判断记录是否满足查询条件
+func (r Query) match(openKFID, externalUserID string, sendTime uint64) bool {
+	if r.OpenKFID != "" && r.OpenKFID != openKFID {
+		return false
+	}
+	if r.ExternalUserID != "" && r.ExternalUserID != externalUserID {
+		return false
+	}
+	if !r.StartTime.IsZero() && sendTime < uint64(r.StartTime.Unix()) {
+		return false
+	}
+	if !r.EndTime.IsZero() && sendTime >= uint64(r.EndTime.Unix()) {
+		return false
+	}
+	return true
+}
+
+// sortRecords 按发送时间排序并截取
+func sortRecords(records []Record, limit int) []Record {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].SendTime < records[j].SendTime
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
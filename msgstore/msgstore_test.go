@@ -0,0 +1,117 @@
+package msgstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testRecords() []Record {
+	return []Record{
+		{MsgID: "1", Direction: DirectionIn, OpenKFID: "kf1", ExternalUserID: "user1", MsgType: "text", SendTime: 300, Data: []byte(`{"text":{"content":"c"}}`)},
+		{MsgID: "2", Direction: DirectionOut, OpenKFID: "kf1", ExternalUserID: "user1", MsgType: "text", SendTime: 100, Data: []byte(`{"text":{"content":"a"}}`)},
+		{MsgID: "3", Direction: DirectionIn, OpenKFID: "kf2", ExternalUserID: "user2", MsgType: "image", SendTime: 200, Data: []byte(`{}`)},
+		{MsgID: "1", Direction: DirectionIn, OpenKFID: "kf1", ExternalUserID: "user1", MsgType: "text", SendTime: 300, Data: []byte(`{}`)},
+	}
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemory() },
+		"file": func(t *testing.T) Store {
+			store, err := NewFile(filepath.Join(t.TempDir(), "messages.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	}
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{name: "all sorted by send time", want: []string{"2", "3", "1"}},
+		{name: "by account", query: Query{OpenKFID: "kf1"}, want: []string{"2", "1"}},
+		{name: "by customer", query: Query{ExternalUserID: "user2"}, want: []string{"3"}},
+		{name: "time range", query: Query{StartTime: time.Unix(100, 0), EndTime: time.Unix(300, 0)}, want: []string{"2", "3"}},
+		{name: "limit", query: Query{Limit: 1}, want: []string{"2"}},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			if err := store.Save(testRecords()...); err != nil {
+				t.Fatal(err)
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					records, err := store.Query(tt.query)
+					if err != nil {
+						t.Fatal(err)
+					}
+					assertMsgIDs(t, records, tt.want)
+				})
+			}
+		})
+	}
+}
+
+func TestFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.jsonl")
+	store, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Save(testRecords()...); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//模拟写入中断留下的不完整记录
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString(`{"msgid":"4","open_kf`)
+	_ = file.Close()
+
+	store, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	records, err := store.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMsgIDs(t, records, []string{"2", "3", "1"})
+	if string(records[2].Data) != `{"text":{"content":"c"}}` {
+		t.Fatalf("Data = %s, want the first saved record", records[2].Data)
+	}
+
+	//截断后继续追加，重复的msgid不会再次保存
+	if err = store.Save(Record{MsgID: "4", OpenKFID: "kf1", SendTime: 400}, Record{MsgID: "2", SendTime: 500}); err != nil {
+		t.Fatal(err)
+	}
+	records, err = store.Query(Query{OpenKFID: "kf1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMsgIDs(t, records, []string{"2", "1", "4"})
+}
+
+func assertMsgIDs(t *testing.T, records []Record, want []string) {
+	t.Helper()
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %v", len(records), want)
+	}
+	for i, record := range records {
+		if record.MsgID != want[i] {
+			t.Fatalf("record %d = %s, want %v", i, record.MsgID, want)
+		}
+	}
+}
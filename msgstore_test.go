@@ -0,0 +1,62 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgstore"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsgonevent"
+)
+
+func TestSendMsgOnEventIsQueryableByRecipient(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/kf/sync_msg"):
+			_, _ = w.Write([]byte(`{"errcode":0,"next_cursor":"c1","has_more":0,"msg_list":[{"msgid":"e1","send_time":1,"origin":4,"msgtype":"event","event":{"event_type":"enter_session","open_kfid":"kf","external_userid":"user","welcome_code":"welcome"}}]}`))
+		case strings.HasSuffix(req.URL.Path, "/kf/service_state/trans"):
+			_, _ = w.Write([]byte(`{"errcode":0,"msg_code":"closing"}`))
+		default:
+			body := make(map[string]interface{})
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body["msgid"]})
+		}
+	})
+	store := msgstore.NewMemory()
+	client := newTestClient(t, Options{CorpID: "corp", MessageStore: store})
+
+	if _, err := client.SyncMsg(SyncMsgOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendMsgOnEvent(sendmsgonevent.NewText("welcome", "hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ServiceStateTrans(ServiceStateTransOptions{OpenKFID: "kf2", ExternalUserID: "user2", ServiceState: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendMsgOnEvent(sendmsgonevent.NewText("closing", "bye")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query msgstore.Query
+		want  int
+	}{
+		{query: msgstore.Query{OpenKFID: "kf", ExternalUserID: "user"}, want: 2},
+		{query: msgstore.Query{OpenKFID: "kf2", ExternalUserID: "user2"}, want: 1},
+	}
+	for _, tt := range tests {
+		records, err := client.QueryMessages(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != tt.want {
+			t.Fatalf("QueryMessages(%+v) = %d records, want %d", tt.query, len(records), tt.want)
+		}
+		last := records[len(records)-1]
+		if last.Direction != msgstore.DirectionOut {
+			t.Fatalf("last record = %+v, want the event response message", last)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgtemplate"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// TemplateVarsOptions 生成模板变量参数
+type TemplateVarsOptions struct {
+	Message  syncmsg.Message   // 收到的消息
+	Customer *CustomerSchema   // 已获取的客户信息，为空时通过客户基本信息获取接口查询客户昵称
+	Extra    map[string]string // 自定义变量
+}
+
+// TemplateVars 根据收到的消息生成模板变量，进入会话事件会附带场景值
+// 未指定 Customer 时每次调用都会请求客户基本信息获取接口，已获取过客户信息时可直接传入
+func (r *Client) TemplateVars(options TemplateVarsOptions) (vars msgtemplate.Vars, err error) {
+	msg := options.Message
+	vars = msgtemplate.Vars{
+		ExternalUserID: msg.ExternalUserID,
+		OpenKFID:       msg.OpenKFID,
+		ServicerUserID: msg.ReceptionistUserID,
+		Extra:          options.Extra,
+	}
+	if msg.EventType == syncmsg.EventTypeEnterSession {
+		event, err := msg.GetEnterSessionEvent()
+		if err != nil {
+			return vars, err
+		}
+		vars.Scene = event.Event.Scene
+		vars.SceneParam = event.Event.SceneParam
+	}
+	if msg.EventType == syncmsg.EventTypeSessionStatusChange {
+		event, err := msg.GetSessionStatusChangeEvent()
+		if err != nil {
+			return vars, err
+		}
+		vars.ServicerUserID = event.Event.NewReceptionistUserID
+	}
+	if options.Customer != nil {
+		vars.NickName = options.Customer.NickName
+		return vars, nil
+	}
+	if msg.ExternalUserID != "" {
+		info, err := r.CustomerBatchGet(CustomerBatchGetOptions{ExternalUserIDList: []string{msg.ExternalUserID}})
+		if err != nil {
+			return vars, err
+		}
+		for _, customer := range info.CustomerList {
+			if customer.ExternalUserID == msg.ExternalUserID {
+				vars.NickName = customer.NickName
+			}
+		}
+	}
+	return vars, nil
+}
+
+// RenderTemplate 根据收到的消息渲染模板，自动填充客户昵称、场景值等变量
+func (r *Client) RenderTemplate(engine *msgtemplate.Engine, name string, options TemplateVarsOptions) (msgtemplate.Rendered, error) {
+	vars, err := r.TemplateVars(options)
+	if err != nil {
+		return msgtemplate.Rendered{}, err
+	}
+	return engine.Render(name, vars)
+}
@@ -0,0 +1,288 @@
+package msgtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsgonevent"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrTemplateNotFound 模板不存在
+var ErrTemplateNotFound = errors.New("模板不存在")
+
+// Template 消息模板，内容使用 text/template 语法，可引用 Vars 中的变量，如 {{.NickName}}
+type Template struct {
+	Name     string `json:"name" yaml:"name"`           // 模板名称，如 welcome、queueing、off_hours、closing
+	OpenKFID string `json:"open_kfid" yaml:"open_kfid"` // 客服帐号ID，不为空时仅对该客服帐号生效
+	Scene    string `json:"scene" yaml:"scene"`         // 场景值，不为空时仅对该场景生效
+	MsgType  string `json:"msgtype" yaml:"msgtype"`     // 消息类型，text 或 msgmenu，默认为 text
+	Text     string `json:"text" yaml:"text"`           // 文本消息内容
+	Menu     Menu   `json:"menu" yaml:"menu"`           // 菜单消息内容
+
+	parsed map[string]*template.Template // 添加时解析的文本模板，键为模板内容
+}
+
+// Menu 菜单消息模板
+type Menu struct {
+	HeadContent string     `json:"head_content" yaml:"head_content"` // 起始文本
+	List        []MenuItem `json:"list" yaml:"list"`                 // 菜单项
+	TailContent string     `json:"tail_content" yaml:"tail_content"` // 结束文本
+}
+
+// MenuItem 菜单项模板
+type MenuItem struct {
+	Type      string `json:"type" yaml:"type"`             // 菜单类型：click、view、miniprogram、text
+	ID        string `json:"id" yaml:"id"`                 // 回复菜单ID
+	URL       string `json:"url" yaml:"url"`               // 超链接菜单的链接
+	AppID     string `json:"appid" yaml:"appid"`           // 小程序菜单的appid
+	PagePath  string `json:"pagepath" yaml:"pagepath"`     // 小程序菜单的页面路径
+	Content   string `json:"content" yaml:"content"`       // 菜单显示内容
+	NoNewline bool   `json:"no_newline" yaml:"no_newline"` // 文本菜单内容后面是否不换行
+}
+
+// Vars 模板变量
+type Vars struct {
+	NickName       string            // 客户微信昵称
+	ExternalUserID string            // 客户UserID
+	OpenKFID       string            // 客服帐号ID
+	Scene          string            // 进入会话的场景值
+	SceneParam     string            // 进入会话的自定义参数
+	ServicerUserID string            // 接待人员userid
+	Extra          map[string]string // 自定义变量，如 {{.Extra.hours}}
+}
+
+// Config 模板配置文件内容
+type Config struct {
+	Templates []Template `json:"templates" yaml:"templates"`
+}
+
+// Rendered 渲染后的消息内容
+type Rendered struct {
+	MsgType string           // 消息类型
+	Text    string           // 文本消息内容
+	Head    string           // 菜单起始文本
+	List    sendmsg.MenuList // 菜单项
+	Tail    string           // 菜单结束文本
+}
+
+// Engine 消息模板引擎，同名模板按 客服帐号+场景值 > 客服帐号 > 场景值 > 通用 的优先级选择
+type Engine struct {
+	mutex     sync.RWMutex
+	templates map[string][]Template
+}
+
+// New 初始化模板引擎
+func New() *Engine {
+	return &Engine{templates: make(map[string][]Template)}
+}
+
+// Add 添加模板，相同名称、客服帐号和场景值的模板会被覆盖，添加时解析模板内容，渲染时不再重复解析
+func (r *Engine) Add(templates ...Template) error {
+	parsed := make([]Template, 0, len(templates))
+	for _, tpl := range templates {
+		if tpl.Name == "" {
+			return errors.New("模板名称不能为空")
+		}
+		if err := tpl.parse(); err != nil {
+			return err
+		}
+		if _, err := tpl.render(Vars{}); err != nil {
+			return err
+		}
+		parsed = append(parsed, tpl)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, tpl := range parsed {
+		list := r.templates[tpl.Name]
+		replaced := false
+		for index, item := range list {
+			if item.OpenKFID == tpl.OpenKFID && item.Scene == tpl.Scene {
+				list[index] = tpl
+				replaced = true
+			}
+		}
+		if !replaced {
+			list = append(list, tpl)
+		}
+		r.templates[tpl.Name] = list
+	}
+	return nil
+}
+
+// Load 从JSON或YAML内容加载模板，format 为 json 或 yaml
+func (r *Engine) Load(reader io.Reader, format string) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	config := Config{}
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		err = json.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return err
+	}
+	return r.Add(config.Templates...)
+}
+
+// LoadFile 从文件加载模板，根据扩展名识别JSON或YAML格式
+func (r *Engine) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.Load(bytes.NewReader(data), strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Render 渲染模板，根据 vars 中的客服帐号和场景值选择模板
+func (r *Engine) Render(name string, vars Vars) (Rendered, error) {
+	tpl, ok := r.lookup(name, vars.OpenKFID, vars.Scene)
+	if !ok {
+		return Rendered{}, ErrTemplateNotFound
+	}
+	return tpl.render(vars)
+}
+
+// lookup 按优先级查找模板
+func (r *Engine) lookup(name, openKFID, scene string) (Template, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	best, bestScore := Template{}, -1
+	for _, tpl := range r.templates[name] {
+		if tpl.OpenKFID != "" && tpl.OpenKFID != openKFID {
+			continue
+		}
+		if tpl.Scene != "" && tpl.Scene != scene {
+			continue
+		}
+		score := 0
+		if tpl.OpenKFID != "" {
+			score += 2
+		}
+		if tpl.Scene != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = tpl, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// parse 解析模板中的所有文本
+func (r *Template) parse() error {
+	texts := []string{r.Text, r.Menu.HeadContent, r.Menu.TailContent}
+	for _, item := range r.Menu.List {
+		texts = append(texts, item.Content, item.URL, item.PagePath)
+	}
+	r.parsed = make(map[string]*template.Template)
+	for _, text := range texts {
+		if !strings.Contains(text, "{{") || r.parsed[text] != nil {
+			continue
+		}
+		tpl, err := template.New("").Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return err
+		}
+		r.parsed[text] = tpl
+	}
+	return nil
+}
+
+func (r Template) render(vars Vars) (info Rendered, err error) {
+	info.MsgType = r.MsgType
+	if info.MsgType == "" {
+		info.MsgType = sendmsg.MsgTypeText
+	}
+	switch info.MsgType {
+	case sendmsg.MsgTypeText:
+		info.Text, err = r.execute(r.Text, vars)
+		return info, err
+	case sendmsg.MsgTypeMenu:
+		if info.Head, err = r.execute(r.Menu.HeadContent, vars); err != nil {
+			return info, err
+		}
+		if info.Tail, err = r.execute(r.Menu.TailContent, vars); err != nil {
+			return info, err
+		}
+		for _, item := range r.Menu.List {
+			menuItem, err := r.renderMenuItem(item, vars)
+			if err != nil {
+				return info, err
+			}
+			info.List = append(info.List, menuItem)
+		}
+		return info, info.List.Validate()
+	}
+	return info, errors.New("不支持的模板消息类型：" + info.MsgType)
+}
+
+func (r Template) renderMenuItem(item MenuItem, vars Vars) (sendmsg.MenuItem, error) {
+	content, err := r.execute(item.Content, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch item.Type {
+	case sendmsg.MenuTypeClick:
+		return sendmsg.NewMenuClick(item.ID, content), nil
+	case sendmsg.MenuTypeView:
+		url, err := r.execute(item.URL, vars)
+		if err != nil {
+			return nil, err
+		}
+		return sendmsg.NewMenuView(url, content), nil
+	case sendmsg.MenuTypeMiniProgram:
+		pagePath, err := r.execute(item.PagePath, vars)
+		if err != nil {
+			return nil, err
+		}
+		return sendmsg.NewMenuMiniProgram(item.AppID, pagePath, content), nil
+	case sendmsg.MenuTypeText:
+		return sendmsg.NewMenuText(content, item.NoNewline), nil
+	}
+	return nil, errors.New("不支持的菜单类型：" + item.Type)
+}
+
+// execute 使用添加时解析的结果渲染单个文本
+func (r Template) execute(text string, vars Vars) (string, error) {
+	tpl := r.parsed[text]
+	if tpl == nil {
+		return text, nil
+	}
+	buf := bytes.Buffer{}
+	if err := tpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SendMsg 生成发送消息请求参数
+func (r Rendered) SendMsg(toUser, openKFID string) interface{} {
+	if r.MsgType == sendmsg.MsgTypeMenu {
+		return sendmsg.NewMenu(toUser, openKFID, r.Head, r.List, r.Tail)
+	}
+	return sendmsg.NewText(toUser, openKFID, r.Text)
+}
+
+// SendMsgOnEvent 生成发送事件响应消息请求参数
+func (r Rendered) SendMsgOnEvent(code string) interface{} {
+	if r.MsgType == sendmsg.MsgTypeMenu {
+		return sendmsgonevent.NewMenu(code, r.Head, r.List, r.Tail)
+	}
+	return sendmsgonevent.NewText(code, r.Text)
+}
@@ -0,0 +1,191 @@
+package msgtemplate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+func TestEngineLookupOrder(t *testing.T) {
+	engine := New()
+	err := engine.Add(
+		Template{Name: "welcome", Text: "generic"},
+		Template{Name: "welcome", Scene: "ad", Text: "scene"},
+		Template{Name: "welcome", OpenKFID: "kf", Text: "account"},
+		Template{Name: "welcome", OpenKFID: "kf", Scene: "ad", Text: "account+scene"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		openKFID string
+		scene    string
+		want     string
+	}{
+		{openKFID: "kf", scene: "ad", want: "account+scene"},
+		{openKFID: "kf", scene: "other", want: "account"},
+		{openKFID: "kf", want: "account"},
+		{openKFID: "other", scene: "ad", want: "scene"},
+		{scene: "ad", want: "scene"},
+		{openKFID: "other", scene: "other", want: "generic"},
+		{want: "generic"},
+	}
+	for _, tt := range tests {
+		info, err := engine.Render("welcome", Vars{OpenKFID: tt.openKFID, Scene: tt.scene})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Text != tt.want {
+			t.Errorf("Render(%q, %q) = %q, want %q", tt.openKFID, tt.scene, info.Text, tt.want)
+		}
+	}
+}
+
+func TestEngineAddReplaces(t *testing.T) {
+	engine := New()
+	if err := engine.Add(Template{Name: "closing", OpenKFID: "kf", Text: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Add(Template{Name: "closing", OpenKFID: "kf", Text: "new {{.NickName}}"}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := engine.Render("closing", Vars{OpenKFID: "kf", NickName: "Tom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Text != "new Tom" {
+		t.Fatalf("Render() = %q, want %q", info.Text, "new Tom")
+	}
+}
+
+func TestEngineLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{
+			name:   "json",
+			format: "json",
+			data:   `{"templates":[{"name":"off_hours","text":"Hi {{.NickName}}, we open at {{.Extra.hours}}"}]}`,
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			data:   "templates:\n  - name: off_hours\n    text: \"Hi {{.NickName}}, we open at {{.Extra.hours}}\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := New()
+			if err := engine.Load(strings.NewReader(tt.data), tt.format); err != nil {
+				t.Fatal(err)
+			}
+			info, err := engine.Render("off_hours", Vars{NickName: "Tom", Extra: map[string]string{"hours": "9:00"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.MsgType != "text" || info.Text != "Hi Tom, we open at 9:00" {
+				t.Fatalf("Render() = %+v", info)
+			}
+		})
+	}
+}
+
+func TestEngineLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.yml")
+	data := "templates:\n  - name: queueing\n    text: please wait\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	engine := New()
+	if err := engine.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	info, err := engine.Render("queueing", Vars{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Text != "please wait" {
+		t.Fatalf("Render() = %q, want %q", info.Text, "please wait")
+	}
+}
+
+func TestRenderMenu(t *testing.T) {
+	engine := New()
+	err := engine.Add(Template{
+		Name:    "welcome",
+		MsgType: "msgmenu",
+		Menu: Menu{
+			HeadContent: "Hi {{.NickName}}",
+			List: []MenuItem{
+				{Type: "click", ID: "101", Content: "yes"},
+				{Type: "view", URL: "https://example.com/?u={{.ExternalUserID}}", Content: "link"},
+				{Type: "miniprogram", AppID: "wx1", PagePath: "index?kf={{.OpenKFID}}", Content: "open"},
+				{Type: "text", Content: "tip", NoNewline: true},
+			},
+			TailContent: "bye",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := engine.Render("welcome", Vars{NickName: "Tom", ExternalUserID: "user", OpenKFID: "kf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.MsgType != "msgmenu" || info.Head != "Hi Tom" || info.Tail != "bye" {
+		t.Fatalf("Render() = %+v", info)
+	}
+	data, err := json.Marshal(info.List)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"type":"click","click":{"id":"101","content":"yes"}},` +
+		`{"type":"view","view":{"url":"https://example.com/?u=user","content":"link"}},` +
+		`{"type":"miniprogram","miniprogram":{"appid":"wx1","pagepath":"index?kf=kf","content":"open"}},` +
+		`{"type":"text","text":{"content":"tip","no_newline":1}}]`
+	if string(data) != want {
+		t.Fatalf("menu = %s, want %s", data, want)
+	}
+}
+
+func textMenuItems(count int) []MenuItem {
+	list := make([]MenuItem, count)
+	for i := range list {
+		list[i] = MenuItem{Type: "text", Content: "tip"}
+	}
+	return list
+}
+
+func TestEngineErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+	}{
+		{name: "empty name", template: Template{Text: "hi"}},
+		{name: "bad syntax", template: Template{Name: "a", Text: "{{.NickName"}},
+		{name: "unknown field", template: Template{Name: "a", Text: "{{.Unknown}}"}},
+		{name: "unsupported msgtype", template: Template{Name: "a", MsgType: "image"}},
+		{name: "unsupported menu type", template: Template{Name: "a", MsgType: "msgmenu", Menu: Menu{List: []MenuItem{{Type: "future"}}}}},
+		{name: "too many menu items", template: Template{Name: "a", MsgType: "msgmenu", Menu: Menu{List: textMenuItems(sendmsg.MaxMenuItems + 1)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().Add(tt.template); err == nil {
+				t.Fatal("Add() err = nil, want error")
+			}
+		})
+	}
+
+	if _, err := New().Render("missing", Vars{}); err != ErrTemplateNotFound {
+		t.Fatalf("Render() err = %v, want %v", err, ErrTemplateNotFound)
+	}
+	if err := New().Load(strings.NewReader("{"), "json"); err == nil {
+		t.Fatal("Load() err = nil, want error")
+	}
+}
@@ -0,0 +1,76 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgtemplate"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	requests := 0
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"errcode":       0,
+			"customer_list": []map[string]string{{"external_userid": "user", "nickname": "Fetched"}},
+		})
+	})
+	client := newTestClient(t, Options{})
+	engine := msgtemplate.New()
+	err := engine.Add(
+		msgtemplate.Template{Name: "welcome", Text: "Hi {{.NickName}}"},
+		msgtemplate.Template{Name: "welcome", Scene: "ad", Text: "Hi {{.NickName}} from {{.SceneParam}}, {{.Extra.hours}}"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := syncmsg.Message{
+		OpenKFID:       "kf",
+		ExternalUserID: "user",
+		MsgType:        "event",
+		EventType:      syncmsg.EventTypeEnterSession,
+		OriginData:     []byte(`{"msgtype":"event","event":{"event_type":"enter_session","open_kfid":"kf","external_userid":"user","scene":"ad","scene_param":"spring"}}`),
+	}
+
+	tests := []struct {
+		name         string
+		options      TemplateVarsOptions
+		want         string
+		wantRequests int
+	}{
+		{
+			name:         "fetches customer",
+			options:      TemplateVarsOptions{Message: msg, Extra: map[string]string{"hours": "9:00"}},
+			want:         "Hi Fetched from spring, 9:00",
+			wantRequests: 1,
+		},
+		{
+			name:    "uses given customer",
+			options: TemplateVarsOptions{Message: msg, Customer: &CustomerSchema{ExternalUserID: "user", NickName: "Given"}},
+			want:    "Hi Given from spring, ",
+		},
+		{
+			name:    "message without customer",
+			options: TemplateVarsOptions{Message: syncmsg.Message{OpenKFID: "kf"}},
+			want:    "Hi ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			info, err := client.RenderTemplate(engine, "welcome", tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Text != tt.want {
+				t.Fatalf("Text = %q, want %q", info.Text, tt.want)
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/outbox"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+const (
+	defaultOutboxMinBackoff  = time.Second
+	defaultOutboxMaxBackoff  = 5 * time.Minute
+	defaultOutboxMaxAttempts = 10
+	defaultOutboxInterval    = time.Second
+	defaultOutboxRetention   = 72 * time.Hour
+	// Run 清理已结束消息的间隔
+	outboxPruneInterval = time.Hour
+)
+
+// OutboxOptions 待发送消息队列参数
+type OutboxOptions struct {
+	Store       outbox.Store  // 待发送消息存储，默认保存在内存中
+	MinBackoff  time.Duration // 首次重试的等待时间，之后每次翻倍，默认1秒
+	MaxBackoff  time.Duration // 最大重试等待时间，默认5分钟
+	MaxAttempts int           // 最多尝试发送次数，默认10次
+	Interval    time.Duration // Run 检查待发送消息的间隔，默认1秒
+	Retention   time.Duration // 已发送和发送失败的消息保留时长，超过后由 Run 或 Prune 清理，默认72小时
+}
+
+// Outbox 待发送消息队列，网络错误或接口超频时按退避策略重试，并根据消息发送失败事件更新消息状态
+type Outbox struct {
+	client  *Client
+	store   outbox.Store
+	options OutboxOptions
+	mutex   sync.Mutex
+	sending map[string]struct{} // 正在发送的消息ID
+}
+
+// NewOutbox 初始化待发送消息队列，拉取到的消息发送失败事件会自动关联到队列中的消息
+func (r *Client) NewOutbox(options OutboxOptions) *Outbox {
+	if options.Store == nil {
+		options.Store = outbox.NewMemory()
+	}
+	if options.MinBackoff == 0 {
+		options.MinBackoff = defaultOutboxMinBackoff
+	}
+	if options.MaxBackoff == 0 {
+		options.MaxBackoff = defaultOutboxMaxBackoff
+	}
+	if options.MaxAttempts == 0 {
+		options.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if options.Interval == 0 {
+		options.Interval = defaultOutboxInterval
+	}
+	if options.Retention == 0 {
+		options.Retention = defaultOutboxRetention
+	}
+	box := &Outbox{
+		client:  r,
+		store:   options.Store,
+		options: options,
+		sending: make(map[string]struct{}),
+	}
+	r.addSyncListener(box.handleSynced)
+	return box
+}
+
+// Enqueue 保存消息并立即尝试发送一次，未指定msgid时使用 Options.MsgIDGenerator 生成，返回消息当前状态
+func (r *Outbox) Enqueue(options interface{}) (entry outbox.Entry, err error) {
+	payload, err := newSendPayload(options)
+	if err != nil {
+		return entry, err
+	}
+	if payload, err = r.client.ensureMsgID(payload); err != nil {
+		return entry, err
+	}
+	now := time.Now()
+	entry = outbox.Entry{
+		MsgID:          payload.MsgID,
+		OpenKFID:       payload.OpenKFID,
+		ExternalUserID: payload.ToUser,
+		Payload:        payload.data,
+		Status:         outbox.StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err = r.store.Put(entry); err != nil {
+		return entry, err
+	}
+	return r.send(entry)
+}
+
+// Status 获取消息发送状态
+func (r *Outbox) Status(msgID string) (outbox.Entry, bool, error) {
+	return r.store.Get(msgID)
+}
+
+// Flush 发送所有已到重试时间的消息
+func (r *Outbox) Flush() error {
+	entries, err := r.store.ListDue(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err = r.send(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune 清理超过保留时长的已发送和发送失败的消息
+func (r *Outbox) Prune() error {
+	_, err := r.store.Prune(time.Now().Add(-r.options.Retention))
+	return err
+}
+
+// Run 定时发送待重试的消息并清理已结束的消息，直到 ctx 取消
+func (r *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(outboxPruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				return err
+			}
+		case <-pruneTicker.C:
+			if err := r.Prune(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// send 发送消息并更新状态，返回的错误仅为存储错误
+// 只在读写存储时加锁，发送请求期间不阻塞其他消息的状态更新
+func (r *Outbox) send(entry outbox.Entry) (outbox.Entry, error) {
+	entry, ok, err := r.startSend(entry)
+	if err != nil || !ok {
+		return entry, err
+	}
+	_, sendErr := r.client.SendMsg(entry.Payload)
+	return r.finishSend(entry, sendErr)
+}
+
+// startSend 标记消息正在发送，消息已被处理或正在发送时返回false
+func (r *Outbox) startSend(entry outbox.Entry) (outbox.Entry, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current, ok, err := r.store.Get(entry.MsgID)
+	if err != nil {
+		return entry, false, err
+	}
+	if ok {
+		entry = current
+	}
+	if _, sending := r.sending[entry.MsgID]; sending || entry.Status != outbox.StatusPending {
+		return entry, false, nil
+	}
+	r.sending[entry.MsgID] = struct{}{}
+	return entry, true, nil
+}
+
+// finishSend 根据发送结果更新消息状态，发送期间已收到发送失败事件时保留失败状态
+func (r *Outbox) finishSend(entry outbox.Entry, sendErr error) (outbox.Entry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.sending, entry.MsgID)
+
+	current, ok, err := r.store.Get(entry.MsgID)
+	if err != nil {
+		return entry, err
+	}
+	if ok {
+		entry = current
+	}
+	entry.Attempts++
+	entry.UpdatedAt = time.Now()
+	switch {
+	case entry.Status != outbox.StatusPending:
+	case sendErr == nil:
+		entry.Status = outbox.StatusSent
+		entry.LastError = ""
+	case isRetryableSendErr(sendErr) && entry.Attempts < r.options.MaxAttempts:
+		entry.LastError = sendErr.Error()
+		entry.NextAttemptAt = entry.UpdatedAt.Add(r.backoff(entry.Attempts))
+	default:
+		entry.Status = outbox.StatusFailed
+		entry.LastError = sendErr.Error()
+	}
+	return entry, r.store.Put(entry)
+}
+
+// backoff 第attempts次发送失败后的等待时间
+func (r *Outbox) backoff(attempts int) time.Duration {
+	backoff := r.options.MinBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= r.options.MaxBackoff {
+			return r.options.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// handleSynced 根据消息发送失败事件将消息标记为失败
+func (r *Outbox) handleSynced(msgList []syncmsg.Message) {
+	for _, msg := range msgList {
+		if msg.EventType != syncmsg.EventTypeMsgSendFail {
+			continue
+		}
+		event, err := msg.GetMsgSendFailEvent()
+		if err != nil {
+			continue
+		}
+		r.markFailed(event.Event.FailMsgID, event.Event.FailType)
+	}
+}
+
+func (r *Outbox) markFailed(msgID string, failType uint32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, ok, err := r.store.Get(msgID)
+	if err != nil || !ok {
+		return
+	}
+	entry.Status = outbox.StatusFailed
+	entry.FailType = failType
+	entry.LastError = "收到消息发送失败事件"
+	entry.UpdatedAt = time.Now()
+	_ = r.store.Put(entry)
+}
+
+// isRetryableSendErr 网络错误和接口超频可重试，其他接口错误不再重试
+func isRetryableSendErr(err error) bool {
+	if err == SDKApiFreqOutOfLimit {
+		return true
+	}
+	_, ok := err.(Error)
+	return !ok
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// File 基于文件的待发送消息存储，每次变更以一行JSON追加写入，打开时按最后一次写入的状态恢复并压缩文件
+type File struct {
+	mutex   sync.RWMutex
+	path    string
+	file    *os.File
+	entries map[string]Entry
+}
+
+// NewFile 打开或创建待发送消息文件
+func NewFile(path string) (*File, error) {
+	store := &File{
+		path:    path,
+		entries: make(map[string]Entry),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// load 读取文件恢复消息状态，末尾不完整的记录会被忽略
+func (r *File) load() error {
+	file, err := os.Open(r.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := Entry{}
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		r.entries[entry.MsgID] = entry
+	}
+}
+
+// compact 只保留每条消息的最新状态重写文件
+func (r *File) compact() error {
+	tmpPath := r.path + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmpFile)
+	for _, entry := range r.entries {
+		if err = writeEntry(writer, entry); err != nil {
+			_ = tmpFile.Close()
+			return err
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, r.path); err != nil {
+		return err
+	}
+	r.file, err = os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND, 0644)
+	return err
+}
+
+func writeEntry(writer io.Writer, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(append(line, '\n'))
+	return err
+}
+
+// Put 新增或更新消息
+func (r *File) Put(entry Entry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := writeEntry(r.file, entry); err != nil {
+		return err
+	}
+	if err := r.file.Sync(); err != nil {
+		return err
+	}
+	r.entries[entry.MsgID] = entry
+	return nil
+}
+
+// Get 根据msgid获取消息
+func (r *File) Get(msgID string) (Entry, bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entry, ok := r.entries[msgID]
+	return entry, ok, nil
+}
+
+// ListDue 获取已到发送时间的待发送消息
+func (r *File) ListDue(now time.Time) ([]Entry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return listDue(r.entries, now), nil
+}
+
+// Prune 删除更新时间早于before的已发送和发送失败的消息，有删除时重写文件
+func (r *File) Prune(before time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	count := prune(r.entries, before)
+	if count == 0 {
+		return 0, nil
+	}
+	if err := r.file.Close(); err != nil {
+		return count, err
+	}
+	return count, r.compact()
+}
+
+// Close 关闭待发送消息文件
+func (r *File) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.file.Close()
+}
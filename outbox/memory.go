@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory 内存待发送消息存储，进程退出后数据丢失
+type Memory struct {
+	mutex   sync.RWMutex
+	entries map[string]Entry
+}
+
+// NewMemory 初始化内存待发送消息存储
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]Entry)}
+}
+
+// Put 新增或更新消息
+func (r *Memory) Put(entry Entry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries[entry.MsgID] = entry
+	return nil
+}
+
+// Get 根据msgid获取消息
+func (r *Memory) Get(msgID string) (Entry, bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entry, ok := r.entries[msgID]
+	return entry, ok, nil
+}
+
+// ListDue 获取已到发送时间的待发送消息
+func (r *Memory) ListDue(now time.Time) ([]Entry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return listDue(r.entries, now), nil
+}
+
+// Prune 删除更新时间早于before的已发送和发送失败的消息
+func (r *Memory) Prune(before time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return prune(r.entries, before), nil
+}
+
+// prune 删除已结束且更新时间早于before的消息
+func prune(entries map[string]Entry, before time.Time) int {
+	count := 0
+	for msgID, entry := range entries {
+		if entry.Status != StatusPending && entry.UpdatedAt.Before(before) {
+			delete(entries, msgID)
+			count++
+		}
+	}
+	return count
+}
+
+// listDue 按创建时间排序返回已到发送时间的待发送消息
+func listDue(entries map[string]Entry, now time.Time) []Entry {
+	result := make([]Entry, 0)
+	for _, entry := range entries {
+		if entry.Status == StatusPending && !entry.NextAttemptAt.After(now) {
+			result = append(result, entry)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status 消息发送状态
+type Status string
+
+const (
+	// StatusPending 待发送或等待重试
+	StatusPending Status = "pending"
+	// StatusSent 接口调用成功
+	StatusSent Status = "sent"
+	// StatusFailed 发送失败，不再重试
+	StatusFailed Status = "failed"
+)
+
+// Entry 待发送消息
+type Entry struct {
+	MsgID          string          `json:"msgid"`           // 消息ID，用于关联消息发送失败事件
+	OpenKFID       string          `json:"open_kfid"`       // 客服帐号ID
+	ExternalUserID string          `json:"external_userid"` // 客户UserID
+	Payload        json.RawMessage `json:"payload"`         // 发送消息请求内容
+	Status         Status          `json:"status"`          // 发送状态
+	Attempts       int             `json:"attempts"`        // 已尝试发送次数
+	LastError      string          `json:"last_error"`      // 最近一次发送失败的原因
+	FailType       uint32          `json:"fail_type"`       // 消息发送失败事件中的失败类型
+	NextAttemptAt  time.Time       `json:"next_attempt_at"` // 下次尝试发送的时间
+	CreatedAt      time.Time       `json:"created_at"`      // 创建时间
+	UpdatedAt      time.Time       `json:"updated_at"`      // 更新时间
+}
+
+// Store 待发送消息存储
+type Store interface {
+	Put(entry Entry) error                  // 新增或更新消息
+	Get(msgID string) (Entry, bool, error)  // 根据msgid获取消息
+	ListDue(now time.Time) ([]Entry, error) // 获取已到发送时间的待发送消息
+	Prune(before time.Time) (int, error)    // 删除更新时间早于before的已发送和发送失败的消息，返回删除的数量
+}
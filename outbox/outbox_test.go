@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestEntry(msgID string, status Status, updatedAt time.Time) Entry {
+	return Entry{
+		MsgID:         msgID,
+		Payload:       []byte(`{"msgid":"` + msgID + `"}`),
+		Status:        status,
+		NextAttemptAt: updatedAt,
+		CreatedAt:     updatedAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemory() },
+		"file": func(t *testing.T) Store {
+			store, err := NewFile(filepath.Join(t.TempDir(), "outbox.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	}
+	now := time.Now()
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			entries := []Entry{
+				newTestEntry("pending", StatusPending, now.Add(-2*time.Hour)),
+				newTestEntry("later", StatusPending, now.Add(time.Hour)),
+				newTestEntry("old-sent", StatusSent, now.Add(-2*time.Hour)),
+				newTestEntry("old-failed", StatusFailed, now.Add(-2*time.Hour)),
+				newTestEntry("new-sent", StatusSent, now),
+			}
+			for _, entry := range entries {
+				if err := store.Put(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			due, err := store.ListDue(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(due) != 1 || due[0].MsgID != "pending" {
+				t.Fatalf("ListDue() = %v, want [pending]", due)
+			}
+
+			count, err := store.Prune(now.Add(-time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != 2 {
+				t.Fatalf("Prune() = %d, want 2", count)
+			}
+			for _, entry := range entries {
+				_, ok, err := store.Get(entry.MsgID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantOK := !strings.HasPrefix(entry.MsgID, "old-")
+				if ok != wantOK {
+					t.Fatalf("Get(%s) found = %v, want %v", entry.MsgID, ok, wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestFileReloadAndCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	entry := newTestEntry("a", StatusPending, now)
+	for i := 0; i < 3; i++ {
+		entry.Attempts = i + 1
+		if err = store.Put(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Put(newTestEntry("b", StatusSent, now.Add(-time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := store.Prune(now.Add(-time.Minute)); err != nil || count != 1 {
+		t.Fatalf("Prune() = %d, %v, want 1, nil", count, err)
+	}
+	//清理后仍可继续写入
+	entry.Status = StatusSent
+	if err = store.Put(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//模拟写入中断留下的不完整记录
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString(`{"msgid":"c","sta`)
+	_ = file.Close()
+
+	store, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	got, ok, err := store.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("Get(a) = %v, %v", ok, err)
+	}
+	if got.Status != StatusSent || got.Attempts != 3 {
+		t.Fatalf("Get(a) = %+v, want latest state", got)
+	}
+	if _, ok, _ = store.Get("b"); ok {
+		t.Fatal("pruned entry b was restored")
+	}
+	if _, ok, _ = store.Get("c"); ok {
+		t.Fatal("truncated entry c was restored")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Fatalf("file has %d lines after compaction, want 1", lines)
+	}
+}
@@ -0,0 +1,77 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/outbox"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+func TestOutboxMarkFailedDuringSend(t *testing.T) {
+	received := make(chan string)
+	release := make(chan struct{})
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body := make(map[string]interface{})
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		received <- body["msgid"].(string)
+		<-release
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body["msgid"]})
+	})
+	client := newTestClient(t, Options{})
+	box := client.NewOutbox(OutboxOptions{})
+
+	result := make(chan outbox.Entry, 1)
+	go func() {
+		entry, err := box.Enqueue(sendmsg.NewText("user", "kf", "hello"))
+		if err != nil {
+			t.Error(err)
+		}
+		result <- entry
+	}()
+	msgID := <-received
+
+	//发送请求未返回时，消息发送失败事件的处理不会被阻塞
+	marked := make(chan struct{})
+	go func() {
+		box.markFailed(msgID, 1)
+		close(marked)
+	}()
+	select {
+	case <-marked:
+	case <-time.After(time.Second):
+		t.Fatal("markFailed blocked behind an in-flight send")
+	}
+
+	//发送中的消息不会被重复发送
+	if err := box.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	entry := <-result
+	if entry.Status != outbox.StatusFailed || entry.FailType != 1 || entry.Attempts != 1 {
+		t.Fatalf("entry = %+v, want failed after one attempt", entry)
+	}
+}
+
+func TestOutboxPrune(t *testing.T) {
+	store := outbox.NewMemory()
+	client := newTestClient(t, Options{})
+	box := client.NewOutbox(OutboxOptions{Store: store, Retention: time.Hour})
+
+	now := time.Now()
+	_ = store.Put(outbox.Entry{MsgID: "old", Status: outbox.StatusSent, UpdatedAt: now.Add(-2 * time.Hour)})
+	_ = store.Put(outbox.Entry{MsgID: "new", Status: outbox.StatusSent, UpdatedAt: now})
+	if err := box.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := box.Status("old"); ok {
+		t.Fatal("expired entry was not pruned")
+	}
+	if _, ok, _ := box.Status("new"); !ok {
+		t.Fatal("recent entry was pruned")
+	}
+}
@@ -0,0 +1,123 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// 不带token拉取消息时的最小轮询间隔，避免触发接口频率限制
+	pollIntervalFloor = 3 * time.Second
+	// 默认最小轮询间隔
+	defaultPollMinInterval = 5 * time.Second
+	// 默认最大轮询间隔
+	defaultPollMaxInterval = time.Minute
+)
+
+// PollerOptions 轮询拉取消息参数
+type PollerOptions struct {
+	OpenKFID     string          // 指定拉取某个客服帐号的消息，与回调触发的拉取共用同一个游标
+	Limit        uint            // 每次拉取的消息数量，默认值和最大值都为1000
+	VoiceFormat  uint32          // 语音消息类型，0-Amr 1-Silk，默认0
+	MinInterval  time.Duration   // 有新消息或还有更多消息时的轮询间隔，也是分页拉取的间隔，不小于3秒，默认5秒
+	MaxInterval  time.Duration   // 空闲时的最大轮询间隔，默认1分钟
+	Handler      SyncHandler     // 消息处理函数
+	ErrorHandler func(err error) // 拉取或处理消息失败时的回调，可为空
+}
+
+// Poller 在无法接收回调时轮询拉取消息，有新消息时加快轮询，空闲时逐步放慢，也可与回调同时运行作为兜底
+type Poller struct {
+	client   *Client
+	options  PollerOptions
+	interval int64 // 当前轮询间隔，原子读写
+}
+
+// NewPoller 初始化轮询实例
+func (r *Client) NewPoller(options PollerOptions) *Poller {
+	if options.MinInterval < pollIntervalFloor {
+		if options.MinInterval == 0 {
+			options.MinInterval = defaultPollMinInterval
+		} else {
+			options.MinInterval = pollIntervalFloor
+		}
+	}
+	if options.MaxInterval == 0 {
+		options.MaxInterval = defaultPollMaxInterval
+	}
+	if options.MaxInterval < options.MinInterval {
+		options.MaxInterval = options.MinInterval
+	}
+	return &Poller{
+		client:   r,
+		options:  options,
+		interval: int64(options.MinInterval),
+	}
+}
+
+// Run 开始轮询，直到 ctx 取消
+func (r *Poller) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		count, hasMore, err := r.poll()
+		r.adjust(count, hasMore, err)
+		if err != nil && r.options.ErrorHandler != nil {
+			r.options.ErrorHandler(err)
+		}
+		timer.Reset(r.Interval())
+	}
+}
+
+// Poll 从已保存的游标拉取一页消息，返回本次处理的消息数
+// 不带token的拉取有严格的频率限制，还有更多消息时由 Run 按最小轮询间隔继续拉取下一页
+func (r *Poller) Poll() (int, error) {
+	count, _, err := r.poll()
+	return count, err
+}
+
+func (r *Poller) poll() (int, bool, error) {
+	options := SyncMsgOptions{
+		Limit:       r.options.Limit,
+		OpenKFID:    r.options.OpenKFID,
+		VoiceFormat: r.options.VoiceFormat,
+	}
+	key := r.client.cursorKey(options.OpenKFID)
+	lock := r.client.syncLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cursor, err := r.client.loadCursor(key, "")
+	if err != nil {
+		return 0, false, err
+	}
+	options.Cursor = cursor
+	return r.client.syncPage(key, &options, r.options.Handler)
+}
+
+// Interval 当前轮询间隔
+func (r *Poller) Interval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&r.interval))
+}
+
+// adjust 根据本次拉取结果调整轮询间隔：有新消息或还有更多消息时恢复最小间隔，空闲或出错时间隔翻倍，接口超频时使用最大间隔
+func (r *Poller) adjust(count int, hasMore bool, err error) {
+	interval := r.Interval()
+	switch {
+	case err == SDKApiFreqOutOfLimit:
+		interval = r.options.MaxInterval
+	case err == nil && (count > 0 || hasMore):
+		interval = r.options.MinInterval
+	default:
+		interval *= 2
+		if interval > r.options.MaxInterval {
+			interval = r.options.MaxInterval
+		}
+	}
+	atomic.StoreInt64(&r.interval, int64(interval))
+}
@@ -0,0 +1,72 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func TestPollerFetchesOnePagePerPoll(t *testing.T) {
+	server := newSyncServer(t,
+		syncPageFixture{NextCursor: "c1", HasMore: 1, MsgIDs: []string{"a", "b"}},
+		syncPageFixture{Cursor: "c1", NextCursor: "c2", MsgIDs: []string{"c"}},
+	)
+	client := newTestClient(t, Options{CorpID: "corp"})
+	var handled []string
+	poller := client.NewPoller(PollerOptions{
+		MaxInterval: time.Minute,
+		Handler: func(msgList []syncmsg.Message) error {
+			handled = append(handled, msgIDs(msgList)...)
+			return nil
+		},
+	})
+
+	count, hasMore, err := poller.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || !hasMore {
+		t.Fatalf("poll() = %d, %v, want 2, true", count, hasMore)
+	}
+	if cursors := server.requestedCursors(); len(cursors) != 1 {
+		t.Fatalf("requested cursors = %v, want a single page", cursors)
+	}
+
+	if count, err = poller.Poll(); err != nil || count != 1 {
+		t.Fatalf("Poll() = %d, %v, want 1, nil", count, err)
+	}
+	if cursors := server.requestedCursors(); len(cursors) != 2 || cursors[1] != "c1" {
+		t.Fatalf("requested cursors = %v, want second page from c1", cursors)
+	}
+	if len(handled) != 3 {
+		t.Fatalf("handled = %v, want [a b c]", handled)
+	}
+}
+
+func TestPollerAdjust(t *testing.T) {
+	tests := []struct {
+		name    string
+		current time.Duration
+		count   int
+		hasMore bool
+		err     error
+		want    time.Duration
+	}{
+		{name: "new messages", current: 20 * time.Second, count: 1, want: 5 * time.Second},
+		{name: "more pages without messages", current: 20 * time.Second, hasMore: true, want: 5 * time.Second},
+		{name: "idle doubles", current: 20 * time.Second, want: 40 * time.Second},
+		{name: "idle is capped", current: 40 * time.Second, want: time.Minute},
+		{name: "frequency limit", current: 5 * time.Second, hasMore: true, err: SDKApiFreqOutOfLimit, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poller := (&Client{}).NewPoller(PollerOptions{})
+			poller.interval = int64(tt.current)
+			poller.adjust(tt.count, tt.hasMore, tt.err)
+			if got := poller.Interval(); got != tt.want {
+				t.Fatalf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+const (
+	// 客户发送消息后可下发的消息条数
+	quotaMaxMsgCount = 5
+	// 客户发送消息后可下发消息的时限
+	quotaWindow = 48 * time.Hour
+)
+
+// QuotaSchema 客户的消息发送额度
+type QuotaSchema struct {
+	IsTracked bool      // 是否有该客户的消息记录，无记录时无法判断额度
+	Remaining int       // 剩余可发送条数
+	ExpireAt  time.Time // 发送时限，超过后需等待客户再次发送消息
+}
+
+// quotaRecord 缓存中的额度记录
+type quotaRecord struct {
+	StartAt   int64 `json:"start_at"`   // 客户最近一次发送消息的时间，unix时间戳
+	SentCount int   `json:"sent_count"` // 此后已发送的消息条数
+}
+
+// QuotaGet 获取客户的消息发送额度，需在初始化时开启 IsEnableQuota
+func (r *Client) QuotaGet(openKFID, externalUserID string) (info QuotaSchema, err error) {
+	record, ok, err := r.getQuotaRecord(openKFID, externalUserID)
+	if err != nil || !ok {
+		return info, err
+	}
+	info.IsTracked = true
+	info.ExpireAt = time.Unix(record.StartAt, 0).Add(quotaWindow)
+	if time.Now().Before(info.ExpireAt) && record.SentCount < quotaMaxMsgCount {
+		info.Remaining = quotaMaxMsgCount - record.SentCount
+	}
+	return info, nil
+}
+
+// checkQuota 发送前校验额度，无记录时不拦截
+func (r *Client) checkQuota(openKFID, externalUserID string) error {
+	if !r.isEnableQuota || externalUserID == "" {
+		return nil
+	}
+	info, err := r.QuotaGet(openKFID, externalUserID)
+	if err != nil {
+		return err
+	}
+	if info.IsTracked && info.Remaining <= 0 {
+		return NewSDKErr(50007)
+	}
+	return nil
+}
+
+// trackSyncedQuota 根据拉取到的客户消息重置额度，根据发送失败事件标记额度已用完
+func (r *Client) trackSyncedQuota(msgList []syncmsg.Message) error {
+	if !r.isEnableQuota {
+		return nil
+	}
+	for _, msg := range msgList {
+		switch {
+		case msg.Origin == 3:
+			if err := r.updateQuota(msg.OpenKFID, msg.ExternalUserID, func(record *quotaRecord) {
+				if int64(msg.SendTime) > record.StartAt {
+					record.StartAt = int64(msg.SendTime)
+					record.SentCount = 0
+				}
+			}); err != nil {
+				return err
+			}
+		case msg.EventType == syncmsg.EventTypeMsgSendFail:
+			event, err := msg.GetMsgSendFailEvent()
+			if err != nil {
+				return err
+			}
+			//4-会话已过期，超过48小时 6-超过5条限制
+			if event.Event.FailType != 4 && event.Event.FailType != 6 {
+				continue
+			}
+			if err = r.updateQuota(msg.OpenKFID, msg.ExternalUserID, func(record *quotaRecord) {
+				if record.StartAt <= int64(msg.SendTime) {
+					record.SentCount = quotaMaxMsgCount
+				}
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// trackSentQuota 发送成功后扣减额度
+func (r *Client) trackSentQuota(openKFID, externalUserID string) error {
+	if !r.isEnableQuota || externalUserID == "" {
+		return nil
+	}
+	return r.updateQuota(openKFID, externalUserID, func(record *quotaRecord) {
+		record.SentCount++
+	})
+}
+
+func (r *Client) updateQuota(openKFID, externalUserID string, update func(record *quotaRecord)) error {
+	r.quotaMutex.Lock()
+	defer r.quotaMutex.Unlock()
+
+	record, _, err := r.getQuotaRecord(openKFID, externalUserID)
+	if err != nil {
+		return err
+	}
+	update(&record)
+	if record.StartAt == 0 {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	//额度记录与发送时限同时过期，缓存过期时间单位为秒
+	expireTime := time.Until(time.Unix(record.StartAt, 0).Add(quotaWindow)) / time.Second
+	if expireTime <= 0 {
+		return nil
+	}
+	if err = r.cache.Set(r.quotaKey(openKFID, externalUserID), string(data), expireTime); err != nil {
+		return NewSDKErr(50002)
+	}
+	return nil
+}
+
+func (r *Client) getQuotaRecord(openKFID, externalUserID string) (record quotaRecord, ok bool, err error) {
+	data, err := r.cache.Get(r.quotaKey(openKFID, externalUserID))
+	if err != nil {
+		return record, false, NewSDKErr(50002)
+	}
+	if data == "" {
+		return record, false, nil
+	}
+	if err = json.Unmarshal([]byte(data), &record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+func (r *Client) quotaKey(openKFID, externalUserID string) string {
+	return "wechat:kf:quota:" + r.corpID + ":" + openKFID + ":" + externalUserID
+}
@@ -0,0 +1,145 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func customerMessage(sendTime time.Time) syncmsg.Message {
+	return syncmsg.Message{OpenKFID: "kf", ExternalUserID: "user", Origin: 3, MsgType: "text", SendTime: uint64(sendTime.Unix())}
+}
+
+func sendFailEvent(sendTime time.Time, failType uint32) syncmsg.Message {
+	data, _ := json.Marshal(map[string]interface{}{
+		"msgtype": "event",
+		"event":   map[string]interface{}{"event_type": "msg_send_fail", "open_kfid": "kf", "external_userid": "user", "fail_type": failType},
+	})
+	return syncmsg.Message{
+		OpenKFID:       "kf",
+		ExternalUserID: "user",
+		Origin:         4,
+		MsgType:        "event",
+		EventType:      syncmsg.EventTypeMsgSendFail,
+		SendTime:       uint64(sendTime.Unix()),
+		OriginData:     data,
+	}
+}
+
+func TestQuota(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		startAt       time.Time // 为零值时不写入初始记录
+		sentCount     int
+		synced        []syncmsg.Message
+		sent          int
+		wantTracked   bool
+		wantRemaining int
+	}{
+		{name: "untracked customer", wantTracked: false},
+		{name: "customer message starts the window", synced: []syncmsg.Message{customerMessage(now)}, wantTracked: true, wantRemaining: 5},
+		{name: "sends decrement", synced: []syncmsg.Message{customerMessage(now)}, sent: 2, wantTracked: true, wantRemaining: 3},
+		{name: "quota runs out", startAt: now, sentCount: 4, sent: 1, wantTracked: true, wantRemaining: 0},
+		{name: "newer customer message resets", startAt: now.Add(-time.Hour), sentCount: 5, synced: []syncmsg.Message{customerMessage(now)}, wantTracked: true, wantRemaining: 5},
+		{name: "older customer message does not reset", startAt: now, sentCount: 5, synced: []syncmsg.Message{customerMessage(now.Add(-time.Hour))}, wantTracked: true, wantRemaining: 0},
+		{name: "window expires after 48h", startAt: now.Add(-quotaWindow - time.Minute), wantTracked: true, wantRemaining: 0},
+		{name: "window still open before 48h", startAt: now.Add(-quotaWindow + time.Minute), sentCount: 1, wantTracked: true, wantRemaining: 4},
+		{name: "send fail over limit exhausts quota", startAt: now.Add(-time.Minute), synced: []syncmsg.Message{sendFailEvent(now, 6)}, wantTracked: true, wantRemaining: 0},
+		{name: "send fail on expired session exhausts quota", startAt: now.Add(-time.Minute), synced: []syncmsg.Message{sendFailEvent(now, 4)}, wantTracked: true, wantRemaining: 0},
+		{name: "other send fail types are ignored", startAt: now.Add(-time.Minute), synced: []syncmsg.Message{sendFailEvent(now, 10)}, wantTracked: true, wantRemaining: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, Options{CorpID: "corp", IsEnableQuota: true})
+			if !tt.startAt.IsZero() {
+				setQuota(t, client, "kf", "user", tt.startAt, tt.sentCount)
+			}
+			if err := client.trackSyncedQuota(tt.synced); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.sent; i++ {
+				if err := client.trackSentQuota("kf", "user"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			info, err := client.QuotaGet("kf", "user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.IsTracked != tt.wantTracked || info.Remaining != tt.wantRemaining {
+				t.Fatalf("QuotaGet() = %+v, want tracked %v remaining %d", info, tt.wantTracked, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestQuotaRecordExpiresWithWindow(t *testing.T) {
+	c := newMemoryCache()
+	client := newTestClient(t, Options{CorpID: "corp", Cache: c, IsEnableQuota: true})
+	startAt := time.Now().Add(-time.Hour)
+	if err := client.trackSyncedQuota([]syncmsg.Message{customerMessage(startAt)}); err != nil {
+		t.Fatal(err)
+	}
+	want := (quotaWindow - time.Hour) / time.Second
+	if got := c.expires[client.quotaKey("kf", "user")]; got < want-5 || got > want {
+		t.Fatalf("expire = %d seconds, want about %d", got, want)
+	}
+}
+
+func TestSendMsgQuota(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		startAt      time.Time
+		sentCount    int
+		wantErr      error
+		wantRequests int
+	}{
+		{name: "exhausted quota fails fast", enabled: true, startAt: time.Now(), sentCount: 5, wantErr: SDKQuotaExceeded},
+		{name: "expired window fails fast", enabled: true, startAt: time.Now().Add(-quotaWindow - time.Minute), wantErr: SDKQuotaExceeded},
+		{name: "remaining quota sends", enabled: true, startAt: time.Now(), sentCount: 4, wantRequests: 1},
+		{name: "untracked customer sends", enabled: true, wantRequests: 1},
+		{name: "tracking disabled sends", startAt: time.Now(), sentCount: 5, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+			})
+			client := newTestClient(t, Options{CorpID: "corp", IsEnableQuota: tt.enabled})
+			if !tt.startAt.IsZero() {
+				setQuota(t, client, "kf", "user", tt.startAt, tt.sentCount)
+			}
+			if _, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello")); err != tt.wantErr {
+				t.Fatalf("SendMsg() err = %v, want %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestSendMsgTracksQuota(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+	})
+	client := newTestClient(t, Options{CorpID: "corp", IsEnableQuota: true})
+	setQuota(t, client, "kf", "user", time.Now(), 3)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello")); err != SDKQuotaExceeded {
+		t.Fatalf("SendMsg() err = %v, want %v", err, SDKQuotaExceeded)
+	}
+}
@@ -0,0 +1,85 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgstore"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/util"
+)
+
+const (
+	// 撤回消息
+	recallMsgAddr = "https://qyapi.weixin.qq.com/cgi-bin/kf/recall_msg?access_token=%s"
+)
+
+// MsgTypeRecall 会话记录中撤回操作的消息类型
+const MsgTypeRecall = "recall"
+
+// RecallMsgOptions 撤回消息请求参数
+type RecallMsgOptions struct {
+	OpenKFID string `json:"open_kfid"` // 客服帐号ID
+	MsgID    string `json:"msgid"`     // 撤回的消息ID
+}
+
+// RecallMsg 撤回通过API发送的消息，仅可撤回2分钟内发送的消息
+// openKFID 为空时使用发送该消息时记录的客服帐号ID，记录在消息发送成功后保留48小时
+// 配置 MessageStore 时会在会话记录中保存撤回操作
+func (r *Client) RecallMsg(openKFID, msgID string) (info BaseModel, err error) {
+	record, ok, err := r.getSentMsgRecord(msgID)
+	if err != nil {
+		return info, err
+	}
+	if openKFID == "" {
+		if !ok {
+			return info, NewSDKErr(50010)
+		}
+		openKFID = record.OpenKFID
+	}
+	options := RecallMsgOptions{OpenKFID: openKFID, MsgID: msgID}
+	data, err := util.HttpPost(fmt.Sprintf(recallMsgAddr, r.accessToken), options)
+	if err != nil {
+		return info, err
+	}
+	_ = json.Unmarshal(data, &info)
+	if info.ErrCode != 0 {
+		return info, NewSDKErr(info.ErrCode, info.ErrMsg)
+	}
+	return info, r.saveRecall(options, record.ExternalUserID)
+}
+
+// getSentMsgRecord 获取已发送消息的记录
+func (r *Client) getSentMsgRecord(msgID string) (record sentMsgRecord, ok bool, err error) {
+	data, err := r.cache.Get(r.sentMsgIDKey(msgID))
+	if err != nil {
+		return record, false, NewSDKErr(50002)
+	}
+	if data == "" {
+		return record, false, nil
+	}
+	if err = json.Unmarshal([]byte(data), &record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+// saveRecall 在会话记录中保存撤回操作
+func (r *Client) saveRecall(options RecallMsgOptions, externalUserID string) error {
+	if r.messageStore == nil {
+		return nil
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return r.messageStore.Save(msgstore.Record{
+		MsgID:          "recall:" + options.MsgID,
+		Direction:      msgstore.DirectionOut,
+		OpenKFID:       options.OpenKFID,
+		ExternalUserID: externalUserID,
+		MsgType:        MsgTypeRecall,
+		SendTime:       uint64(time.Now().Unix()),
+		Data:           data,
+	})
+}
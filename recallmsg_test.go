@@ -0,0 +1,52 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/msgstore"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+func TestRecallMsg(t *testing.T) {
+	var recalls []RecallMsgOptions
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/kf/recall_msg") {
+			var options RecallMsgOptions
+			_ = json.NewDecoder(req.Body).Decode(&options)
+			recalls = append(recalls, options)
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+			return
+		}
+		body := make(map[string]interface{})
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body["msgid"]})
+	})
+	store := msgstore.NewMemory()
+	client := newTestClient(t, Options{CorpID: "corp", MessageStore: store})
+
+	if _, err := client.RecallMsg("", "unknown"); err != SDKSentMsgNotFound {
+		t.Fatalf("err = %v, want %v", err, SDKSentMsgNotFound)
+	}
+
+	info, err := client.SendMsg(sendmsg.NewText("user", "kf", "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.RecallMsg("", info.MsgID); err != nil {
+		t.Fatal(err)
+	}
+	if len(recalls) != 1 || recalls[0].OpenKFID != "kf" || recalls[0].MsgID != info.MsgID {
+		t.Fatalf("recalls = %+v, want open_kfid from the sent record", recalls)
+	}
+
+	records, err := client.QueryMessages(msgstore.Query{OpenKFID: "kf", ExternalUserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1].MsgType != MsgTypeRecall {
+		t.Fatalf("records = %+v, want the sent message and its recall", records)
+	}
+}
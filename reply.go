@@ -0,0 +1,64 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// ReplyText 回复文本消息，接收人和客服帐号取自收到的消息
+// Reply 系列方法发送前会先获取会话状态，会话状态不允许通过API发送消息时返回 SDKServiceStateNotAllowed
+func (r *Client) ReplyText(msg syncmsg.Message, content string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewText(msg.ExternalUserID, msg.OpenKFID, content))
+}
+
+// ReplyImage 回复图片消息
+func (r *Client) ReplyImage(msg syncmsg.Message, mediaID string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewImage(msg.ExternalUserID, msg.OpenKFID, mediaID))
+}
+
+// ReplyVoice 回复语音消息
+func (r *Client) ReplyVoice(msg syncmsg.Message, mediaID string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewVoice(msg.ExternalUserID, msg.OpenKFID, mediaID))
+}
+
+// ReplyVideo 回复视频消息
+func (r *Client) ReplyVideo(msg syncmsg.Message, mediaID string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewVideo(msg.ExternalUserID, msg.OpenKFID, mediaID))
+}
+
+// ReplyFile 回复文件消息
+func (r *Client) ReplyFile(msg syncmsg.Message, mediaID string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewFile(msg.ExternalUserID, msg.OpenKFID, mediaID))
+}
+
+// ReplyLink 回复图文链接消息
+func (r *Client) ReplyLink(msg syncmsg.Message, title, desc, url, thumbMediaID string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewLink(msg.ExternalUserID, msg.OpenKFID, title, desc, url, thumbMediaID))
+}
+
+// ReplyMenu 回复菜单消息
+func (r *Client) ReplyMenu(msg syncmsg.Message, headContent string, list sendmsg.MenuList, tailContent string) (info SendMsgSchema, err error) {
+	return r.reply(msg, sendmsg.NewMenu(msg.ExternalUserID, msg.OpenKFID, headContent, list, tailContent))
+}
+
+// reply 校验会话状态后发送回复消息，仅“未处理”和“由智能助手接待”状态下允许通过API发送消息
+// 校验会话状态需在发送前额外调用一次获取会话状态接口，初始化时开启 IsSkipReplyStateCheck 可跳过校验
+func (r *Client) reply(msg syncmsg.Message, options interface{}) (info SendMsgSchema, err error) {
+	if msg.ExternalUserID == "" || msg.OpenKFID == "" {
+		return info, NewSDKErr(50004)
+	}
+	if r.skipReplyState {
+		return r.SendMsg(options)
+	}
+	state, err := r.ServiceStateGet(ServiceStateGetOptions{
+		OpenKFID:       msg.OpenKFID,
+		ExternalUserID: msg.ExternalUserID,
+	})
+	if err != nil {
+		return info, err
+	}
+	if state.ServiceState != 0 && state.ServiceState != 1 {
+		return info, NewSDKErr(50005)
+	}
+	return r.SendMsg(options)
+}
@@ -0,0 +1,56 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+func TestReplyServiceState(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         int
+		skipCheck     bool
+		msg           syncmsg.Message
+		wantErr       error
+		wantStateGets int
+		wantSends     int
+	}{
+		{name: "untreated session", state: 0, wantStateGets: 1, wantSends: 1},
+		{name: "assistant session", state: 1, wantStateGets: 1, wantSends: 1},
+		{name: "queueing session", state: 2, wantErr: SDKServiceStateNotAllowed, wantStateGets: 1},
+		{name: "servicer session", state: 3, wantErr: SDKServiceStateNotAllowed, wantStateGets: 1},
+		{name: "closed session", state: 4, wantErr: SDKServiceStateNotAllowed, wantStateGets: 1},
+		{name: "check skipped", state: 3, skipCheck: true, wantSends: 1},
+		{name: "missing reply target", msg: syncmsg.Message{OpenKFID: "kf"}, wantErr: SDKReplyTargetMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateGets, sends := 0, 0
+			newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+				if strings.HasSuffix(req.URL.Path, "/service_state/get") {
+					stateGets++
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "service_state": tt.state})
+					return
+				}
+				sends++
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0})
+			})
+			client := newTestClient(t, Options{CorpID: "corp", IsSkipReplyStateCheck: tt.skipCheck})
+			msg := tt.msg
+			if msg.OpenKFID == "" && msg.ExternalUserID == "" {
+				msg = syncmsg.Message{OpenKFID: "kf", ExternalUserID: "user"}
+			}
+
+			if _, err := client.ReplyText(msg, "hello"); err != tt.wantErr {
+				t.Fatalf("ReplyText() err = %v, want %v", err, tt.wantErr)
+			}
+			if stateGets != tt.wantStateGets || sends != tt.wantSends {
+				t.Fatalf("state gets = %d, sends = %d, want %d and %d", stateGets, sends, tt.wantStateGets, tt.wantSends)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/util"
+)
+
+// 临时素材media_id的缓存有效期，单位为秒。素材3天内有效，预留1小时避免临近过期时使用
+const mediaIDExpireTime = (3*24 - 1) * 3600
+
+// MediaSource 素材来源，复制后共享已读取的内容
+type MediaSource struct {
+	fileName string
+	url      string // 网络素材链接，有效期内相同链接只下载和上传一次
+	content  *mediaContent
+}
+
+// mediaContent 素材内容，buffered 为true时首次读取成功后保存在内存中供重复使用
+type mediaContent struct {
+	mutex    sync.Mutex
+	read     func() ([]byte, error)
+	buffered bool
+	loaded   bool
+	data     []byte
+}
+
+func (r *mediaContent) load() ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.loaded {
+		return r.data, nil
+	}
+	data, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if r.buffered {
+		r.data, r.loaded = data, true
+	}
+	return data, nil
+}
+
+// MediaFromFile 从本地文件读取素材，每次上传前重新读取文件
+func MediaFromFile(filePath string) MediaSource {
+	return MediaSource{
+		fileName: filepath.Base(filePath),
+		content: &mediaContent{read: func() ([]byte, error) {
+			return ioutil.ReadFile(filePath)
+		}},
+	}
+}
+
+// MediaFromReader 从 io.Reader 读取素材，内容在首次读取后保存在内存中，同一来源可重复发送
+func MediaFromReader(fileName string, reader io.Reader) MediaSource {
+	return MediaSource{
+		fileName: fileName,
+		content: &mediaContent{buffered: true, read: func() ([]byte, error) {
+			return ioutil.ReadAll(reader)
+		}},
+	}
+}
+
+// MediaFromURL 下载网络素材，文件名取自链接路径
+// 有效期内相同链接直接复用已上传的media_id，不会重新下载，链接内容更新后需更换链接
+func MediaFromURL(rawURL string) MediaSource {
+	fileName := path.Base(rawURL)
+	if info, err := url.Parse(rawURL); err == nil {
+		fileName = path.Base(info.Path)
+	}
+	return MediaSource{
+		fileName: fileName,
+		url:      rawURL,
+		content: &mediaContent{buffered: true, read: func() ([]byte, error) {
+			return util.HttpGet(rawURL)
+		}},
+	}
+}
+
+// SendMediaOptions 发送素材消息参数
+type SendMediaOptions struct {
+	ToUser   string      // 接收消息的客户UserID
+	OpenKFID string      // 发送消息的客服帐号ID
+	Source   MediaSource // 素材来源
+}
+
+// SendImage 上传图片并发送图片消息
+func (r *Client) SendImage(options SendMediaOptions) (info SendMsgSchema, err error) {
+	mediaID, err := r.UploadMedia("image", options.Source)
+	if err != nil {
+		return info, err
+	}
+	return r.SendMsg(sendmsg.NewImage(options.ToUser, options.OpenKFID, mediaID))
+}
+
+// SendVoice 上传语音并发送语音消息
+func (r *Client) SendVoice(options SendMediaOptions) (info SendMsgSchema, err error) {
+	mediaID, err := r.UploadMedia("voice", options.Source)
+	if err != nil {
+		return info, err
+	}
+	return r.SendMsg(sendmsg.NewVoice(options.ToUser, options.OpenKFID, mediaID))
+}
+
+// SendVideo 上传视频并发送视频消息
+func (r *Client) SendVideo(options SendMediaOptions) (info SendMsgSchema, err error) {
+	mediaID, err := r.UploadMedia("video", options.Source)
+	if err != nil {
+		return info, err
+	}
+	return r.SendMsg(sendmsg.NewVideo(options.ToUser, options.OpenKFID, mediaID))
+}
+
+// SendFile 上传文件并发送文件消息
+func (r *Client) SendFile(options SendMediaOptions) (info SendMsgSchema, err error) {
+	mediaID, err := r.UploadMedia("file", options.Source)
+	if err != nil {
+		return info, err
+	}
+	return r.SendMsg(sendmsg.NewFile(options.ToUser, options.OpenKFID, mediaID))
+}
+
+// UploadMedia 上传临时素材并返回media_id，相同类型和内容的素材在有效期内只上传一次
+func (r *Client) UploadMedia(mediaType string, source MediaSource) (string, error) {
+	if source.content == nil {
+		return "", NewSDKErr(50009)
+	}
+	keys := make([]string, 0, 2)
+	if source.url != "" {
+		key := r.mediaKey(mediaType, "url", []byte(source.url))
+		if mediaID, err := r.getMediaID(key); mediaID != "" || err != nil {
+			return mediaID, err
+		}
+		keys = append(keys, key)
+	}
+
+	body, err := source.content.load()
+	if err != nil {
+		return "", err
+	}
+	key := r.mediaKey(mediaType, "content", body)
+	mediaID, err := r.getMediaID(key)
+	if err != nil {
+		return "", err
+	}
+	if mediaID == "" {
+		info, err := r.MediaOriginUpload(source.fileName, mediaType, len(body), body)
+		if err != nil {
+			return "", err
+		}
+		mediaID = info.MediaID
+	}
+	keys = append(keys, key)
+	for _, key := range keys {
+		if err = r.cache.Set(key, mediaID, mediaIDExpireTime); err != nil {
+			return "", NewSDKErr(50002)
+		}
+	}
+	return mediaID, nil
+}
+
+// getMediaID 读取缓存的media_id
+func (r *Client) getMediaID(key string) (string, error) {
+	mediaID, err := r.cache.Get(key)
+	if err != nil {
+		return "", NewSDKErr(50002)
+	}
+	return mediaID, nil
+}
+
+// mediaKey 已上传素材的缓存键，kind 区分按素材链接还是按内容缓存
+func (r *Client) mediaKey(mediaType, kind string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return "wechat:kf:media:" + r.corpID + ":" + mediaType + ":" + kind + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// mediaServer 模拟素材上传、网络素材下载和发送消息接口
+type mediaServer struct {
+	mutex     sync.Mutex
+	uploads   []int // 每次上传的文件大小
+	downloads int
+	mediaIDs  []string // 发送消息中的media_id
+}
+
+func newMediaServer(t *testing.T) *mediaServer {
+	server := &mediaServer{}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/media/upload"):
+			file, _, err := req.FormFile("media")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			data, _ := ioutil.ReadAll(file)
+			server.uploads = append(server.uploads, len(data))
+			_, _ = w.Write([]byte(`{"errcode":0,"type":"image","media_id":"media-1"}`))
+		case strings.HasSuffix(req.URL.Path, "/image.png"):
+			server.downloads++
+			_, _ = w.Write([]byte("remote image"))
+		default:
+			body := struct {
+				MsgID string `json:"msgid"`
+				Image struct {
+					MediaID string `json:"media_id"`
+				} `json:"image"`
+			}{}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			server.mediaIDs = append(server.mediaIDs, body.Image.MediaID)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body.MsgID})
+		}
+	})
+	return server
+}
+
+func TestSendImageReusesReaderSource(t *testing.T) {
+	server := newMediaServer(t)
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	options := SendMediaOptions{ToUser: "user", OpenKFID: "kf", Source: MediaFromReader("image.png", bytes.NewBufferString("local image"))}
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendImage(options); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(server.uploads) != 1 || server.uploads[0] != len("local image") {
+		t.Fatalf("uploads = %v, want a single upload of the full content", server.uploads)
+	}
+	if len(server.mediaIDs) != 2 || server.mediaIDs[1] != "media-1" {
+		t.Fatalf("sent media ids = %v, want media-1 twice", server.mediaIDs)
+	}
+}
+
+func TestUploadMediaCachesURLSources(t *testing.T) {
+	server := newMediaServer(t)
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	for i := 0; i < 2; i++ {
+		mediaID, err := client.UploadMedia("image", MediaFromURL("https://example.com/static/image.png?v=1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mediaID != "media-1" {
+			t.Fatalf("UploadMedia() = %q, want media-1", mediaID)
+		}
+	}
+	if server.downloads != 1 || len(server.uploads) != 1 {
+		t.Fatalf("downloads = %d, uploads = %d, want one of each", server.downloads, len(server.uploads))
+	}
+
+	//不同链接的相同内容复用已上传的素材
+	if _, err := client.UploadMedia("image", MediaFromURL("https://example.com/static/image.png?v=2")); err != nil {
+		t.Fatal(err)
+	}
+	if server.downloads != 2 || len(server.uploads) != 1 {
+		t.Fatalf("downloads = %d, uploads = %d, want 2 downloads and 1 upload", server.downloads, len(server.uploads))
+	}
+}
+
+func TestUploadMediaWithoutSource(t *testing.T) {
+	client := newTestClient(t, Options{})
+	if _, err := client.UploadMedia("image", MediaSource{}); err != SDKMediaSourceMissing {
+		t.Fatalf("err = %v, want %v", err, SDKMediaSourceMissing)
+	}
+}
@@ -20,19 +20,60 @@ type SendMsgSchema struct {
 // SendMsg 发送消息
 // 当微信客户处于“新接入待处理”或“由智能助手接待”状态下，可调用该接口给用户发送消息。
 // 注意仅当微信客户在主动发送消息给客服后的48小时内，企业可发送消息给客户，最多可发送5条消息；若用户继续发送消息，企业可再次下发消息。
-// 支持发送消息类型：文本、图片、语音、视频、文件、图文、小程序、菜单消息、地理位置。
+// 支持发送消息类型：文本、图片、语音、视频、文件、图文、小程序、菜单消息、地理位置、获客链接。
+// 使用 sendmsg 包中的消息类型时，发送前会先校验参数。
 // 目前该接口允许下发消息条数和下发时限如下：
 //
 // 用户动作	允许下发条数限制	下发时限
 // 用户发送消息	5条	48 小时
+//
+// 初始化时开启 IsEnableQuota 后，会根据拉取到的客户消息和已发送的消息跟踪额度，额度不足时直接返回 SDKQuotaExceeded
+//
+// 未指定msgid时使用 Options.MsgIDGenerator 自动生成，相同msgid在48小时内已发送成功时直接返回成功，不会重复请求接口
+// 请求失败时返回的 MsgID 为本次请求使用的msgid，超时等情况下需在请求参数中指定该msgid后重试，
+// 或使用 HashMsgID 作为 Options.MsgIDGenerator，才能避免消息被重复发送
 func (r *Client) SendMsg(options interface{}) (info SendMsgSchema, err error) {
-	data, err := util.HttpPost(fmt.Sprintf(sendMsgAddr, r.accessToken), options)
+	payload, err := newSendPayload(options)
+	if err != nil {
+		return info, err
+	}
+	if payload, err = r.ensureMsgID(payload); err != nil {
+		return info, err
+	}
+	info.MsgID = payload.MsgID
+	//相同msgid已发送成功时直接返回，重试不会重复发送
+	sent, err := r.isMsgIDSent(payload.MsgID)
+	if err != nil {
+		return info, err
+	}
+	if sent {
+		return info, nil
+	}
+	if err = r.checkQuota(payload.OpenKFID, payload.ToUser); err != nil {
+		return info, err
+	}
+	data, err := util.HttpPost(fmt.Sprintf(sendMsgAddr, r.accessToken), payload.data)
 	if err != nil {
 		return info, err
 	}
 	_ = json.Unmarshal(data, &info)
+	if r.isDuplicateMsgID(info.ErrCode) {
+		//之前的请求已发送成功但未收到响应，按发送成功处理
+		info.BaseModel = BaseModel{}
+		info.MsgID = payload.MsgID
+	}
 	if info.ErrCode != 0 {
 		return info, NewSDKErr(info.ErrCode, info.ErrMsg)
 	}
+	//消息已发送成功，记录失败时同时返回发送结果和错误
+	if err = r.markMsgIDSent(payload); err != nil {
+		return info, err
+	}
+	if err = r.trackSentQuota(payload.OpenKFID, payload.ToUser); err != nil {
+		return info, err
+	}
+	if err = r.saveSentMessage(payload, info.MsgID); err != nil {
+		return info, err
+	}
 	return info, nil
 }
@@ -0,0 +1,138 @@
+package sendmsg
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// MaxMenuItems 菜单项数量上限
+const MaxMenuItems = 10
+
+// ErrMenuItemsExceeded 菜单项超过数量上限
+var ErrMenuItemsExceeded = errors.New("菜单项不能多于10个")
+
+// MenuItem 菜单项，由 MenuClick、MenuView、MenuMiniProgram、MenuText 实现，序列化时自动填充对应的type
+type MenuItem interface {
+	MenuType() string
+}
+
+// MenuList 菜单项列表，序列化时校验数量上限，反序列化时按type解析为对应的菜单项
+type MenuList []MenuItem
+
+// MenuUnknown 暂不支持的菜单类型，保留原始内容
+type MenuUnknown struct {
+	Type string          // 菜单类型
+	Raw  json.RawMessage // 原始内容
+}
+
+// MenuType 菜单类型
+func (r MenuClick) MenuType() string { return MenuTypeClick }
+
+// MenuType 菜单类型
+func (r MenuView) MenuType() string { return MenuTypeView }
+
+// MenuType 菜单类型
+func (r MenuMiniProgram) MenuType() string { return MenuTypeMiniProgram }
+
+// MenuType 菜单类型
+func (r MenuText) MenuType() string { return MenuTypeText }
+
+// MenuType 菜单类型
+func (r MenuUnknown) MenuType() string { return r.Type }
+
+// MarshalJSON 序列化时填充菜单类型
+func (r MenuClick) MarshalJSON() ([]byte, error) {
+	type menuClick MenuClick
+	r.Type = MenuTypeClick
+	return json.Marshal(menuClick(r))
+}
+
+// MarshalJSON 序列化时填充菜单类型
+func (r MenuView) MarshalJSON() ([]byte, error) {
+	type menuView MenuView
+	r.Type = MenuTypeView
+	return json.Marshal(menuView(r))
+}
+
+// MarshalJSON 序列化时填充菜单类型
+func (r MenuMiniProgram) MarshalJSON() ([]byte, error) {
+	type menuMiniProgram MenuMiniProgram
+	r.Type = MenuTypeMiniProgram
+	return json.Marshal(menuMiniProgram(r))
+}
+
+// MarshalJSON 序列化时填充菜单类型
+func (r MenuText) MarshalJSON() ([]byte, error) {
+	type menuText MenuText
+	r.Type = MenuTypeText
+	return json.Marshal(menuText(r))
+}
+
+// MarshalJSON 原样输出原始内容
+func (r MenuUnknown) MarshalJSON() ([]byte, error) {
+	return r.Raw, nil
+}
+
+// Validate 校验菜单项数量
+func (r MenuList) Validate() error {
+	if len(r) > MaxMenuItems {
+		return ErrMenuItemsExceeded
+	}
+	return nil
+}
+
+// MarshalJSON 校验菜单项数量后序列化
+func (r MenuList) MarshalJSON() ([]byte, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]MenuItem(r))
+}
+
+// UnmarshalJSON 按type解析菜单项
+func (r *MenuList) UnmarshalJSON(data []byte) error {
+	var rawList []json.RawMessage
+	if err := json.Unmarshal(data, &rawList); err != nil {
+		return err
+	}
+	list := make(MenuList, 0, len(rawList))
+	for _, raw := range rawList {
+		header := struct {
+			Type string `json:"type"`
+		}{}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return err
+		}
+		var item MenuItem
+		var err error
+		switch header.Type {
+		case MenuTypeClick:
+			info := MenuClick{}
+			err = json.Unmarshal(raw, &info)
+			item = info
+		case MenuTypeView:
+			info := MenuView{}
+			err = json.Unmarshal(raw, &info)
+			item = info
+		case MenuTypeMiniProgram:
+			info := MenuMiniProgram{}
+			err = json.Unmarshal(raw, &info)
+			item = info
+		case MenuTypeText:
+			info := MenuText{}
+			err = json.Unmarshal(raw, &info)
+			item = info
+		default:
+			item = MenuUnknown{Type: header.Type, Raw: raw}
+		}
+		if err != nil {
+			return err
+		}
+		list = append(list, item)
+	}
+	*r = list
+	return nil
+}
@@ -0,0 +1,89 @@
+package sendmsg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMenuListMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    MenuList
+		want    string
+		wantErr error
+	}{
+		{name: "nil list", list: nil, want: `[]`},
+		{
+			name: "fills menu types",
+			list: MenuList{NewMenuClick("101", "yes"), NewMenuView("https://example.com", "link"), NewMenuText("tip", true)},
+			want: `[{"type":"click","click":{"id":"101","content":"yes"}},{"type":"view","view":{"url":"https://example.com","content":"link"}},{"type":"text","text":{"content":"tip","no_newline":1}}]`,
+		},
+		{
+			name: "type set by caller is overridden",
+			list: MenuList{MenuClick{Type: "view"}},
+			want: `[{"type":"click","click":{"id":"","content":""}}]`,
+		},
+		{
+			name: "unknown items keep raw content",
+			list: MenuList{MenuUnknown{Type: "future", Raw: json.RawMessage(`{"type":"future","future":{"x":1}}`)}},
+			want: `[{"type":"future","future":{"x":1}}]`,
+		},
+		{name: "too many items", list: make(MenuList, MaxMenuItems+1), wantErr: ErrMenuItemsExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.list)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("Marshal() err = nil, want %v", tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenuListUnmarshal(t *testing.T) {
+	data := `[{"type":"click","click":{"id":"101","content":"yes"}},{"type":"miniprogram","miniprogram":{"appid":"wx1","pagepath":"index","content":"open"}},{"type":"future","future":{"x":1}}]`
+	var list MenuList
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("got %d items, want 3", len(list))
+	}
+	click, ok := list[0].(MenuClick)
+	if !ok || click.Click.ID != "101" {
+		t.Fatalf("item 0 = %#v, want click 101", list[0])
+	}
+	miniProgram, ok := list[1].(MenuMiniProgram)
+	if !ok || miniProgram.MiniProgram.AppID != "wx1" {
+		t.Fatalf("item 1 = %#v, want miniprogram wx1", list[1])
+	}
+	if unknown, ok := list[2].(MenuUnknown); !ok || unknown.MenuType() != "future" {
+		t.Fatalf("item 2 = %#v, want unknown future", list[2])
+	}
+
+	//反序列化后再次序列化保持原样
+	again, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got interface{}
+	_ = json.Unmarshal([]byte(data), &want)
+	_ = json.Unmarshal(again, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("round trip = %s, want %s", again, data)
+	}
+
+	if err = json.Unmarshal([]byte(`{}`), &list); err == nil {
+		t.Fatal("expected error for non-array menu list")
+	}
+}
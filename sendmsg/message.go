@@ -10,10 +10,13 @@ type Message struct {
 // Text 发送文本消息
 type Text struct {
 	Message
-	MsgType string `json:"msgtype"` // 消息类型，此时固定为：text
-	Text    struct {
-		Content string `json:"content"` // 消息内容，最长不超过2048个字节
-	} `json:"text"` // 文本消息
+	MsgType string      `json:"msgtype"` // 消息类型，此时固定为：text
+	Text    TextContent `json:"text"`    // 文本消息
+}
+
+// TextContent 文本消息内容，发送事件响应消息时共用
+type TextContent struct {
+	Content string `json:"content"` // 消息内容，最长不超过2048个字节
 }
 
 // Image 发送图片消息
@@ -79,12 +82,15 @@ type MiniProgram struct {
 // Menu 发送菜单消息
 type Menu struct {
 	Message
-	MsgType string `json:"msgtype"` // 消息类型，此时固定为：msgmenu
-	MsgMenu struct {
-		HeadContent string        `json:"head_content"` // 消息内容，不多于1024字节
-		List        []interface{} `json:"list"`         // 菜单项配置，不能多余10个
-		TailContent string        `json:"tail_content"` // 结束文本, 不多于1024字
-	} `json:"msgmenu"`
+	MsgType string      `json:"msgtype"` // 消息类型，此时固定为：msgmenu
+	MsgMenu MenuContent `json:"msgmenu"`
+}
+
+// MenuContent 菜单消息内容，发送事件响应消息时共用
+type MenuContent struct {
+	HeadContent string   `json:"head_content"` // 消息内容，不多于1024字节
+	List        MenuList `json:"list"`         // 菜单项配置，不能多余10个
+	TailContent string   `json:"tail_content"` // 结束文本, 不多于1024字
 }
 
 // MenuClick 回复菜单
@@ -115,6 +121,15 @@ type MenuMiniProgram struct {
 	} `json:"miniprogram"`
 }
 
+// MenuText 文本菜单
+type MenuText struct {
+	Type string `json:"type"` // 菜单类型: text 文本
+	Text struct {
+		Content   string `json:"content"`    // 文本内容，支持\n换行, 不少于1字节, 不多于256字节
+		NoNewline uint32 `json:"no_newline"` // 内容后面是否不换行，0-换行 1-不换行，默认为0
+	} `json:"text"`
+}
+
 // Location 地理位置消息
 type Location struct {
 	Message
@@ -126,3 +141,12 @@ type Location struct {
 		Address   string  `json:"address"`   // 地址详情说明
 	} `json:"location"`
 }
+
+// CaLink 获客链接消息
+type CaLink struct {
+	Message
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：ca_link
+	CaLink  struct {
+		LinkURL string `json:"link_url"` // 通过获客助手创建的获客链接
+	} `json:"ca_link"`
+}
@@ -1 +1,205 @@
 package sendmsg
+
+// 消息类型
+const (
+	MsgTypeText        = "text"        // 文本消息
+	MsgTypeImage       = "image"       // 图片消息
+	MsgTypeVoice       = "voice"       // 语音消息
+	MsgTypeVideo       = "video"       // 视频消息
+	MsgTypeFile        = "file"        // 文件消息
+	MsgTypeLink        = "link"        // 图文链接消息
+	MsgTypeMiniProgram = "miniprogram" // 小程序消息
+	MsgTypeMenu        = "msgmenu"     // 菜单消息
+	MsgTypeLocation    = "location"    // 地理位置消息
+	MsgTypeCaLink      = "ca_link"     // 获客链接消息
+)
+
+// 菜单类型
+const (
+	MenuTypeClick       = "click"       // 回复菜单
+	MenuTypeView        = "view"        // 超链接菜单
+	MenuTypeMiniProgram = "miniprogram" // 小程序菜单
+	MenuTypeText        = "text"        // 文本菜单
+)
+
+// NewText 创建文本消息
+func NewText(toUser, openKFID, content string) Text {
+	return Text{Message: newMessage(toUser, openKFID), MsgType: MsgTypeText, Text: TextContent{Content: content}}
+}
+
+// NewImage 创建图片消息
+func NewImage(toUser, openKFID, mediaID string) Image {
+	info := Image{Message: newMessage(toUser, openKFID), MsgType: MsgTypeImage}
+	info.Image.MediaID = mediaID
+	return info
+}
+
+// NewVoice 创建语音消息
+func NewVoice(toUser, openKFID, mediaID string) Voice {
+	info := Voice{Message: newMessage(toUser, openKFID), MsgType: MsgTypeVoice}
+	info.Voice.MediaID = mediaID
+	return info
+}
+
+// NewVideo 创建视频消息
+func NewVideo(toUser, openKFID, mediaID string) Video {
+	info := Video{Message: newMessage(toUser, openKFID), MsgType: MsgTypeVideo}
+	info.Video.MediaID = mediaID
+	return info
+}
+
+// NewFile 创建文件消息
+func NewFile(toUser, openKFID, mediaID string) File {
+	info := File{Message: newMessage(toUser, openKFID), MsgType: MsgTypeFile}
+	info.File.MediaID = mediaID
+	return info
+}
+
+// NewLink 创建图文链接消息
+func NewLink(toUser, openKFID, title, desc, url, thumbMediaID string) Link {
+	info := Link{Message: newMessage(toUser, openKFID), MsgType: MsgTypeLink}
+	info.Link.Title = title
+	info.Link.Desc = desc
+	info.Link.URL = url
+	info.Link.ThumbMediaID = thumbMediaID
+	return info
+}
+
+// NewMiniProgram 创建小程序消息
+func NewMiniProgram(toUser, openKFID, appID, title, thumbMediaID, pagePath string) MiniProgram {
+	info := MiniProgram{Message: newMessage(toUser, openKFID), MsgType: MsgTypeMiniProgram}
+	info.MiniProgram.AppID = appID
+	info.MiniProgram.Title = title
+	info.MiniProgram.ThumbMediaID = thumbMediaID
+	info.MiniProgram.PagePath = pagePath
+	return info
+}
+
+// NewLocation 创建地理位置消息
+func NewLocation(toUser, openKFID string, latitude, longitude float32, name, address string) Location {
+	info := Location{Message: newMessage(toUser, openKFID), MsgType: MsgTypeLocation}
+	info.Location.Latitude = latitude
+	info.Location.Longitude = longitude
+	info.Location.Name = name
+	info.Location.Address = address
+	return info
+}
+
+// NewMenu 创建菜单消息
+func NewMenu(toUser, openKFID, headContent string, list MenuList, tailContent string) Menu {
+	return Menu{
+		Message: newMessage(toUser, openKFID),
+		MsgType: MsgTypeMenu,
+		MsgMenu: MenuContent{HeadContent: headContent, List: list, TailContent: tailContent},
+	}
+}
+
+// NewCaLink 创建获客链接消息
+func NewCaLink(toUser, openKFID, linkURL string) CaLink {
+	info := CaLink{Message: newMessage(toUser, openKFID), MsgType: MsgTypeCaLink}
+	info.CaLink.LinkURL = linkURL
+	return info
+}
+
+// NewMenuClick 创建回复菜单项
+func NewMenuClick(id, content string) MenuClick {
+	info := MenuClick{Type: MenuTypeClick}
+	info.Click.ID = id
+	info.Click.Content = content
+	return info
+}
+
+// NewMenuView 创建超链接菜单项
+func NewMenuView(url, content string) MenuView {
+	info := MenuView{Type: MenuTypeView}
+	info.View.URL = url
+	info.View.Content = content
+	return info
+}
+
+// NewMenuMiniProgram 创建小程序菜单项
+func NewMenuMiniProgram(appID, pagePath, content string) MenuMiniProgram {
+	info := MenuMiniProgram{Type: MenuTypeMiniProgram}
+	info.MiniProgram.AppID = appID
+	info.MiniProgram.PagePath = pagePath
+	info.MiniProgram.Content = content
+	return info
+}
+
+// NewMenuText 创建文本菜单项，noNewline为true时内容后面不换行
+func NewMenuText(content string, noNewline bool) MenuText {
+	info := MenuText{Type: MenuTypeText}
+	info.Text.Content = content
+	if noNewline {
+		info.Text.NoNewline = 1
+	}
+	return info
+}
+
+// MenuBuilder 菜单消息构建器
+//
+//	menu := sendmsg.NewMenuBuilder(toUser, openKFID).
+//		Head("请选择").
+//		Click("101", "满意").
+//		Click("102", "不满意").
+//		Tail("感谢您的评价").
+//		Build()
+type MenuBuilder struct {
+	menu Menu
+}
+
+// NewMenuBuilder 创建菜单消息构建器
+func NewMenuBuilder(toUser, openKFID string) *MenuBuilder {
+	return &MenuBuilder{menu: NewMenu(toUser, openKFID, "", nil, "")}
+}
+
+// MsgID 指定消息ID
+func (r *MenuBuilder) MsgID(msgID string) *MenuBuilder {
+	r.menu.MsgID = msgID
+	return r
+}
+
+// Head 设置起始文本
+func (r *MenuBuilder) Head(content string) *MenuBuilder {
+	r.menu.MsgMenu.HeadContent = content
+	return r
+}
+
+// Tail 设置结束文本
+func (r *MenuBuilder) Tail(content string) *MenuBuilder {
+	r.menu.MsgMenu.TailContent = content
+	return r
+}
+
+// Click 添加回复菜单项
+func (r *MenuBuilder) Click(id, content string) *MenuBuilder {
+	r.menu.MsgMenu.List = append(r.menu.MsgMenu.List, NewMenuClick(id, content))
+	return r
+}
+
+// View 添加超链接菜单项
+func (r *MenuBuilder) View(url, content string) *MenuBuilder {
+	r.menu.MsgMenu.List = append(r.menu.MsgMenu.List, NewMenuView(url, content))
+	return r
+}
+
+// MiniProgram 添加小程序菜单项
+func (r *MenuBuilder) MiniProgram(appID, pagePath, content string) *MenuBuilder {
+	r.menu.MsgMenu.List = append(r.menu.MsgMenu.List, NewMenuMiniProgram(appID, pagePath, content))
+	return r
+}
+
+// Text 添加文本菜单项
+func (r *MenuBuilder) Text(content string, noNewline bool) *MenuBuilder {
+	r.menu.MsgMenu.List = append(r.menu.MsgMenu.List, NewMenuText(content, noNewline))
+	return r
+}
+
+// Build 生成菜单消息，菜单项超过10个时发送会返回 ErrMenuItemsExceeded
+func (r *MenuBuilder) Build() Menu {
+	return r.menu
+}
+
+func newMessage(toUser, openKFID string) Message {
+	return Message{ToUser: toUser, OpenKFID: openKFID}
+}
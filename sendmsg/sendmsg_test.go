@@ -0,0 +1,100 @@
+package sendmsg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message interface{}
+		want    string
+	}{
+		{
+			name:    "text",
+			message: NewText("user", "kf", "hello"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"text","text":{"content":"hello"}}`,
+		},
+		{
+			name:    "image",
+			message: NewImage("user", "kf", "media"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"image","image":{"media_id":"media"}}`,
+		},
+		{
+			name:    "voice",
+			message: NewVoice("user", "kf", "media"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"voice","voice":{"media_id":"media"}}`,
+		},
+		{
+			name:    "video",
+			message: NewVideo("user", "kf", "media"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"video","video":{"media_id":"media"}}`,
+		},
+		{
+			name:    "file",
+			message: NewFile("user", "kf", "media"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"file","file":{"media_id":"media"}}`,
+		},
+		{
+			name:    "link",
+			message: NewLink("user", "kf", "title", "desc", "https://example.com", "thumb"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"link","link":{"title":"title","desc":"desc","url":"https://example.com","thumb_media_id":"thumb"}}`,
+		},
+		{
+			name:    "miniprogram",
+			message: NewMiniProgram("user", "kf", "wx1", "title", "thumb", "index"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"miniprogram","miniprogram":{"appid":"wx1","title":"title","thumb_media_id":"thumb","pagepath":"index"}}`,
+		},
+		{
+			name:    "location",
+			message: NewLocation("user", "kf", 1.5, 2.5, "name", "address"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"location","location":{"latitude":1.5,"longitude":2.5,"name":"name","address":"address"}}`,
+		},
+		{
+			name:    "ca_link",
+			message: NewCaLink("user", "kf", "https://work.weixin.qq.com/ca/abc"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"ca_link","ca_link":{"link_url":"https://work.weixin.qq.com/ca/abc"}}`,
+		},
+		{
+			name:    "menu",
+			message: NewMenu("user", "kf", "head", MenuList{NewMenuClick("1", "yes")}, "tail"),
+			want:    `{"touser":"user","open_kfid":"kf","msgid":"","msgtype":"msgmenu","msgmenu":{"head_content":"head","list":[{"type":"click","click":{"id":"1","content":"yes"}}],"tail_content":"tail"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenuBuilder(t *testing.T) {
+	menu := NewMenuBuilder("user", "kf").
+		MsgID("m1").
+		Head("head").
+		Click("1", "yes").
+		View("https://example.com", "link").
+		MiniProgram("wx1", "index", "open").
+		Text("tip", true).
+		Tail("tail").
+		Build()
+
+	want := NewMenu("user", "kf", "head", MenuList{
+		NewMenuClick("1", "yes"),
+		NewMenuView("https://example.com", "link"),
+		NewMenuMiniProgram("wx1", "index", "open"),
+		NewMenuText("tip", true),
+	}, "tail")
+	want.MsgID = "m1"
+	if !reflect.DeepEqual(menu, want) {
+		t.Fatalf("Build() = %+v, want %+v", menu, want)
+	}
+}
@@ -0,0 +1,65 @@
+package sendmsg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTextBytes 文本消息内容的最大字节数
+const MaxTextBytes = 2048
+
+// 句子结束符，在没有段落和换行可拆分时优先在句末拆分
+var sentenceTerminators = []string{"。", "！", "？", "；", "…", "!", "?", ";", ". "}
+
+// SplitText 将超长文本按字节数拆分为多段，依次优先在段落、换行、句末和空白处拆分，不会拆开UTF-8字符
+func SplitText(content string, maxBytes int) []string {
+	if maxBytes <= 0 {
+		maxBytes = MaxTextBytes
+	}
+	parts := make([]string, 0, len(content)/maxBytes+1)
+	for len(content) > maxBytes {
+		cut := splitPoint(content, maxBytes)
+		if part := strings.TrimRight(content[:cut], " \n"); part != "" {
+			parts = append(parts, part)
+		}
+		content = strings.TrimLeft(content[cut:], " \n")
+	}
+	if content != "" {
+		parts = append(parts, content)
+	}
+	return parts
+}
+
+// splitPoint 在前maxBytes字节内查找拆分位置
+func splitPoint(content string, maxBytes int) int {
+	//保证拆分位置在UTF-8字符边界上
+	limit := maxBytes
+	for limit > 0 && !utf8.RuneStart(content[limit]) {
+		limit--
+	}
+	if limit == 0 {
+		//单个字符超过maxBytes时按字符拆分
+		_, size := utf8.DecodeRuneInString(content)
+		return size
+	}
+	window := content[:limit]
+	if index := strings.LastIndex(window, "\n\n"); index > 0 {
+		return index + 2
+	}
+	if index := strings.LastIndex(window, "\n"); index > 0 {
+		return index + 1
+	}
+	best := 0
+	for _, terminator := range sentenceTerminators {
+		if index := strings.LastIndex(window, terminator); index >= 0 && index+len(terminator) > best {
+			best = index + len(terminator)
+		}
+	}
+	if best > 0 {
+		return best
+	}
+	if index := strings.LastIndexAny(window, " \t"); index > 0 {
+		return index + 1
+	}
+	return limit
+}
@@ -0,0 +1,54 @@
+package sendmsg
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxBytes int
+		want     []string
+	}{
+		{name: "short text", content: "hello", maxBytes: 10, want: []string{"hello"}},
+		{name: "empty text", content: "", maxBytes: 10, want: []string{}},
+		{name: "prefers paragraphs", content: "aaaa\n\nbbbb\ncc", maxBytes: 12, want: []string{"aaaa", "bbbb\ncc"}},
+		{name: "then lines", content: "aaaa\nbbbb cc", maxBytes: 10, want: []string{"aaaa", "bbbb cc"}},
+		{name: "then sentences", content: "你好。再见吧", maxBytes: 12, want: []string{"你好。", "再见吧"}},
+		{name: "then spaces", content: "aaa bbb ccc", maxBytes: 8, want: []string{"aaa bbb", "ccc"}},
+		{name: "hard cut on rune boundary", content: "你好世界", maxBytes: 7, want: []string{"你好", "世界"}},
+		{name: "rune larger than limit", content: "你好", maxBytes: 2, want: []string{"你", "好"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitText(tt.content, tt.maxBytes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SplitText() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("SplitText() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitTextLimits(t *testing.T) {
+	content := strings.Repeat("微信客服消息拆分测试。", 500)
+	parts := SplitText(content, 0)
+	if len(parts) < 2 {
+		t.Fatalf("got %d parts, want the default limit to split the text", len(parts))
+	}
+	if joined := strings.Join(parts, ""); joined != content {
+		t.Fatal("parts do not add up to the original text")
+	}
+	for i, part := range parts {
+		if len(part) > MaxTextBytes || !utf8.ValidString(part) {
+			t.Fatalf("part %d has %d bytes or invalid UTF-8", i, len(part))
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package sendmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// msgIDPattern 消息ID取值范围
+var msgIDPattern = regexp.MustCompile(`^[0-9a-zA-Z_-]{0,32}$`)
+
+// Validator 可在发送前校验参数的消息
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError 消息参数校验错误
+type ValidationError struct {
+	Field  string // 参数名
+	Reason string // 错误原因
+}
+
+// Error 输出错误信息
+func (r ValidationError) Error() string {
+	return r.Field + ": " + r.Reason
+}
+
+// ValidateMsgID 校验消息ID，不多于32字节，取值范围：[0-9a-zA-Z_-]
+func ValidateMsgID(msgID string) error {
+	if !msgIDPattern.MatchString(msgID) {
+		return ValidationError{Field: "msgid", Reason: "不多于32字节，取值范围为[0-9a-zA-Z_-]"}
+	}
+	return nil
+}
+
+// ValidateLength 校验参数长度，min为0时允许为空
+func ValidateLength(field, value string, min, max int) error {
+	if len(value) < min {
+		return ValidationError{Field: field, Reason: "不能为空"}
+	}
+	if max > 0 && len(value) > max {
+		return ValidationError{Field: field, Reason: "超过长度限制"}
+	}
+	return nil
+}
+
+func (r Message) validate() error {
+	if err := ValidateLength("touser", r.ToUser, 1, 0); err != nil {
+		return err
+	}
+	if err := ValidateLength("open_kfid", r.OpenKFID, 1, 0); err != nil {
+		return err
+	}
+	return ValidateMsgID(r.MsgID)
+}
+
+// ValidateMsgType 校验消息类型
+func ValidateMsgType(msgType, expected string) error {
+	if msgType != expected {
+		return ValidationError{Field: "msgtype", Reason: "应为" + expected}
+	}
+	return nil
+}
+
+func validateURL(field, url string, max int) error {
+	if err := ValidateLength(field, url, 1, max); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return ValidationError{Field: field, Reason: "需包含协议头(http/https)"}
+	}
+	return nil
+}
+
+func validateAll(checks ...error) error {
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate 校验文本消息
+func (r Text) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeText),
+		r.Text.Validate(),
+	)
+}
+
+// Validate 校验文本消息内容
+func (r TextContent) Validate() error {
+	return ValidateLength("text.content", r.Content, 1, 2048)
+}
+
+// Validate 校验图片消息
+func (r Image) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeImage),
+		ValidateLength("image.media_id", r.Image.MediaID, 1, 0),
+	)
+}
+
+// Validate 校验语音消息
+func (r Voice) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeVoice),
+		ValidateLength("voice.media_id", r.Voice.MediaID, 1, 0),
+	)
+}
+
+// Validate 校验视频消息
+func (r Video) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeVideo),
+		ValidateLength("video.media_id", r.Video.MediaID, 1, 0),
+	)
+}
+
+// Validate 校验文件消息
+func (r File) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeFile),
+		ValidateLength("file.media_id", r.File.MediaID, 1, 0),
+	)
+}
+
+// Validate 校验图文链接消息，标题和描述超长时由接口自动截断
+func (r Link) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeLink),
+		ValidateLength("link.title", r.Link.Title, 1, 0),
+		validateURL("link.url", r.Link.URL, 2048),
+		ValidateLength("link.thumb_media_id", r.Link.ThumbMediaID, 1, 0),
+	)
+}
+
+// Validate 校验小程序消息，标题超长时由接口自动截断
+func (r MiniProgram) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeMiniProgram),
+		ValidateLength("miniprogram.appid", r.MiniProgram.AppID, 1, 0),
+		ValidateLength("miniprogram.thumb_media_id", r.MiniProgram.ThumbMediaID, 1, 0),
+		ValidateLength("miniprogram.pagepath", r.MiniProgram.PagePath, 1, 0),
+	)
+}
+
+// Validate 校验菜单消息
+func (r Menu) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeMenu),
+		r.MsgMenu.Validate(),
+	)
+}
+
+// Validate 校验菜单消息内容
+func (r MenuContent) Validate() error {
+	return validateAll(
+		ValidateLength("msgmenu.head_content", r.HeadContent, 0, 1024),
+		ValidateLength("msgmenu.tail_content", r.TailContent, 0, 1024),
+		r.List.ValidateItems(),
+	)
+}
+
+// Validate 校验地理位置消息
+func (r Location) Validate() error {
+	if r.Location.Latitude < -90 || r.Location.Latitude > 90 {
+		return ValidationError{Field: "location.latitude", Reason: "范围为90 ~ -90"}
+	}
+	if r.Location.Longitude < -180 || r.Location.Longitude > 180 {
+		return ValidationError{Field: "location.longitude", Reason: "范围为180 ~ -180"}
+	}
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeLocation),
+	)
+}
+
+// Validate 校验获客链接消息
+func (r CaLink) Validate() error {
+	return validateAll(
+		r.Message.validate(),
+		ValidateMsgType(r.MsgType, MsgTypeCaLink),
+		validateURL("ca_link.link_url", r.CaLink.LinkURL, 0),
+	)
+}
+
+// ValidateItems 校验菜单项数量及每个菜单项的参数
+func (r MenuList) ValidateItems() error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	for _, item := range r {
+		if validator, ok := item.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate 校验回复菜单
+func (r MenuClick) Validate() error {
+	return validateAll(
+		ValidateLength("click.id", r.Click.ID, 1, 64),
+		ValidateLength("click.content", r.Click.Content, 1, 128),
+	)
+}
+
+// Validate 校验超链接菜单
+func (r MenuView) Validate() error {
+	return validateAll(
+		validateURL("view.url", r.View.URL, 2048),
+		ValidateLength("view.content", r.View.Content, 1, 1024),
+	)
+}
+
+// Validate 校验小程序菜单
+func (r MenuMiniProgram) Validate() error {
+	return validateAll(
+		ValidateLength("miniprogram.appid", r.MiniProgram.AppID, 1, 32),
+		ValidateLength("miniprogram.pagepath", r.MiniProgram.PagePath, 1, 1024),
+		ValidateLength("miniprogram.content", r.MiniProgram.Content, 1, 1024),
+	)
+}
+
+// Validate 校验文本菜单
+func (r MenuText) Validate() error {
+	return ValidateLength("text.content", r.Text.Content, 1, 256)
+}
@@ -0,0 +1,98 @@
+package sendmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	withMsgID := func(message Text, msgID string) Text {
+		message.MsgID = msgID
+		return message
+	}
+	wrongType := NewImage("user", "kf", "media")
+	wrongType.MsgType = MsgTypeText
+	tests := []struct {
+		name      string
+		message   Validator
+		wantField string // 为空时期望校验通过
+	}{
+		{name: "text", message: NewText("user", "kf", "hello")},
+		{name: "text at 2048 bytes", message: NewText("user", "kf", strings.Repeat("a", 2048))},
+		{name: "text over 2048 bytes", message: NewText("user", "kf", strings.Repeat("a", 2049)), wantField: "text.content"},
+		{name: "text counts bytes not runes", message: NewText("user", "kf", strings.Repeat("中", 683)), wantField: "text.content"},
+		{name: "empty text", message: NewText("user", "kf", ""), wantField: "text.content"},
+		{name: "missing touser", message: NewText("", "kf", "hello"), wantField: "touser"},
+		{name: "missing open_kfid", message: NewText("user", "", "hello"), wantField: "open_kfid"},
+		{name: "valid msgid", message: withMsgID(NewText("user", "kf", "hello"), "abc_DEF-123")},
+		{name: "msgid with invalid characters", message: withMsgID(NewText("user", "kf", "hello"), "abc.def"), wantField: "msgid"},
+		{name: "msgid over 32 bytes", message: withMsgID(NewText("user", "kf", "hello"), strings.Repeat("a", 33)), wantField: "msgid"},
+		{name: "wrong msgtype", message: wrongType, wantField: "msgtype"},
+
+		{name: "image", message: NewImage("user", "kf", "media")},
+		{name: "image without media_id", message: NewImage("user", "kf", ""), wantField: "image.media_id"},
+		{name: "voice", message: NewVoice("user", "kf", "media")},
+		{name: "voice without media_id", message: NewVoice("user", "kf", ""), wantField: "voice.media_id"},
+		{name: "video", message: NewVideo("user", "kf", "media")},
+		{name: "video without media_id", message: NewVideo("user", "kf", ""), wantField: "video.media_id"},
+		{name: "file", message: NewFile("user", "kf", "media")},
+		{name: "file without media_id", message: NewFile("user", "kf", ""), wantField: "file.media_id"},
+
+		{name: "link", message: NewLink("user", "kf", "title", "desc", "https://example.com", "thumb")},
+		{name: "link without title", message: NewLink("user", "kf", "", "desc", "https://example.com", "thumb"), wantField: "link.title"},
+		{name: "link without protocol", message: NewLink("user", "kf", "title", "desc", "example.com", "thumb"), wantField: "link.url"},
+		{name: "link url over 2048 bytes", message: NewLink("user", "kf", "title", "desc", "https://"+strings.Repeat("a", 2041), "thumb"), wantField: "link.url"},
+		{name: "link without thumb", message: NewLink("user", "kf", "title", "desc", "https://example.com", ""), wantField: "link.thumb_media_id"},
+
+		{name: "miniprogram", message: NewMiniProgram("user", "kf", "wx1", "title", "thumb", "index")},
+		{name: "miniprogram without appid", message: NewMiniProgram("user", "kf", "", "title", "thumb", "index"), wantField: "miniprogram.appid"},
+		{name: "miniprogram without thumb", message: NewMiniProgram("user", "kf", "wx1", "title", "", "index"), wantField: "miniprogram.thumb_media_id"},
+		{name: "miniprogram without pagepath", message: NewMiniProgram("user", "kf", "wx1", "title", "thumb", ""), wantField: "miniprogram.pagepath"},
+
+		{name: "location", message: NewLocation("user", "kf", 39.9, 116.4, "name", "address")},
+		{name: "location at bounds", message: NewLocation("user", "kf", -90, 180, "name", "address")},
+		{name: "latitude out of range", message: NewLocation("user", "kf", 90.5, 116.4, "name", "address"), wantField: "location.latitude"},
+		{name: "longitude out of range", message: NewLocation("user", "kf", 39.9, -180.5, "name", "address"), wantField: "location.longitude"},
+
+		{name: "ca_link", message: NewCaLink("user", "kf", "https://work.weixin.qq.com/ca/abc")},
+		{name: "ca_link without url", message: NewCaLink("user", "kf", ""), wantField: "ca_link.link_url"},
+		{name: "ca_link without protocol", message: NewCaLink("user", "kf", "work.weixin.qq.com/ca/abc"), wantField: "ca_link.link_url"},
+
+		{name: "menu", message: NewMenu("user", "kf", "head", MenuList{NewMenuClick("1", "yes")}, "tail")},
+		{name: "menu head over 1024 bytes", message: NewMenu("user", "kf", strings.Repeat("a", 1025), nil, ""), wantField: "msgmenu.head_content"},
+		{name: "menu tail over 1024 bytes", message: NewMenu("user", "kf", "", nil, strings.Repeat("a", 1025)), wantField: "msgmenu.tail_content"},
+		{name: "menu click without id", message: NewMenu("user", "kf", "", MenuList{NewMenuClick("", "yes")}, ""), wantField: "click.id"},
+		{name: "menu click content over 128 bytes", message: NewMenu("user", "kf", "", MenuList{NewMenuClick("1", strings.Repeat("a", 129))}, ""), wantField: "click.content"},
+		{name: "menu view without protocol", message: NewMenu("user", "kf", "", MenuList{NewMenuView("example.com", "link")}, ""), wantField: "view.url"},
+		{name: "menu miniprogram appid over 32 bytes", message: NewMenu("user", "kf", "", MenuList{NewMenuMiniProgram(strings.Repeat("a", 33), "index", "open")}, ""), wantField: "miniprogram.appid"},
+		{name: "menu text over 256 bytes", message: NewMenu("user", "kf", "", MenuList{NewMenuText(strings.Repeat("a", 257), false)}, ""), wantField: "text.content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() err = %v, want nil", err)
+				}
+				return
+			}
+			validationErr, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("Validate() err = %v, want ValidationError on %s", err, tt.wantField)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Fatalf("Validate() field = %s, want %s", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateMenuItemsExceeded(t *testing.T) {
+	list := make(MenuList, MaxMenuItems+1)
+	for i := range list {
+		list[i] = NewMenuText("tip", false)
+	}
+	if err := NewMenu("user", "kf", "", list, "").Validate(); err != ErrMenuItemsExceeded {
+		t.Fatalf("Validate() err = %v, want %v", err, ErrMenuItemsExceeded)
+	}
+}
@@ -32,14 +32,46 @@ type SendMsgOnEventSchema struct {
 //
 //「进入会话事件」响应消息：
 // 如果满足通过API下发欢迎语条件（条件为：1. 企业没有在管理端配置了原生欢迎语；2. 用户在过去48小时里未收过欢迎语，且未向该用户发过消息），则用户进入会话事件会额外返回一个welcome_code，开发者以此为凭据调用接口（填到该接口code参数），即可向客户发送客服欢迎语。
+//
+// 未指定msgid时使用 Options.MsgIDGenerator 自动生成，相同msgid在48小时内已发送成功时直接返回成功，不会重复请求接口
+// 请求失败时返回的 MsgID 为本次请求使用的msgid，超时等情况下需在请求参数中指定该msgid后重试，
+// 或使用 HashMsgID 作为 Options.MsgIDGenerator，才能避免消息被重复发送
 func (r *Client) SendMsgOnEvent(options interface{}) (info SendMsgOnEventSchema, err error) {
-	data, err := util.HttpPost(fmt.Sprintf(sendMsgOnEventAddr, r.accessToken), options)
+	payload, err := newSendPayload(options)
+	if err != nil {
+		return info, err
+	}
+	if payload, err = r.ensureMsgID(payload); err != nil {
+		return info, err
+	}
+	info.MsgID = payload.MsgID
+	//相同msgid已发送成功时直接返回，重试不会重复发送
+	sent, err := r.isMsgIDSent(payload.MsgID)
+	if err != nil {
+		return info, err
+	}
+	if sent {
+		return info, nil
+	}
+	data, err := util.HttpPost(fmt.Sprintf(sendMsgOnEventAddr, r.accessToken), payload.data)
 	if err != nil {
 		return info, err
 	}
 	_ = json.Unmarshal(data, &info)
+	if r.isDuplicateMsgID(info.ErrCode) {
+		//之前的请求已发送成功但未收到响应，按发送成功处理
+		info.BaseModel = BaseModel{}
+		info.MsgID = payload.MsgID
+	}
 	if info.ErrCode != 0 {
 		return info, NewSDKErr(info.ErrCode, info.ErrMsg)
 	}
+	//消息已发送成功，记录失败时同时返回发送结果和错误
+	if err = r.markMsgIDSent(payload); err != nil {
+		return info, err
+	}
+	if err = r.saveSentMessage(payload, info.MsgID); err != nil {
+		return info, err
+	}
 	return info, nil
 }
@@ -1,5 +1,7 @@
 package sendmsgonevent
 
+import "github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+
 // Message 发送事件响应消息
 type Message struct {
 	Code  string `json:"code"`  // 事件响应消息对应的code。通过事件回调下发，仅可使用一次。
@@ -9,47 +11,31 @@ type Message struct {
 // Text 文本消息
 type Text struct {
 	Message
-	MsgType string `json:"msgtype"` // 消息类型，此时固定为：text
-	Text    struct {
-		Content string `json:"content"` // 消息内容，最长不超过2048个字节
-	} `json:"text"` // 文本消息
+	MsgType string              `json:"msgtype"` // 消息类型，此时固定为：text
+	Text    sendmsg.TextContent `json:"text"`    // 文本消息，与发送消息使用相同的内容类型
 }
 
 // Menu 发送菜单消息
 type Menu struct {
 	Message
-	MsgType string `json:"msgtype"` // 消息类型，此时固定为：msgmenu
-	MsgMenu struct {
-		HeadContent string        `json:"head_content"` // 消息内容，不多于1024字节
-		List        []interface{} `json:"list"`         // 菜单项配置，不能多余10个
-		TailContent string        `json:"tail_content"` // 结束文本, 不多于1024字
-	} `json:"msgmenu"`
+	MsgType string              `json:"msgtype"` // 消息类型，此时固定为：msgmenu
+	MsgMenu sendmsg.MenuContent `json:"msgmenu"` // 菜单消息，与发送消息使用相同的内容类型
 }
 
+// MenuItem 菜单项，与发送消息使用相同的菜单项类型
+type MenuItem = sendmsg.MenuItem
+
+// MenuList 菜单项列表，不能多于10个
+type MenuList = sendmsg.MenuList
+
 // MenuClick 回复菜单
-type MenuClick struct {
-	Type  string `json:"type"` // 菜单类型: click 回复菜单
-	Click struct {
-		ID      string `json:"id"`      // 菜单ID, 不少于1字节, 不多于64字节
-		Content string `json:"content"` // 菜单显示内容, 不少于1字节, 不多于128字节
-	} `json:"click"`
-}
+type MenuClick = sendmsg.MenuClick
 
 // MenuView 超链接菜单
-type MenuView struct {
-	Type string `json:"type"` // 菜单类型: view 超链接菜单
-	View struct {
-		URL     string `json:"url"`     // 点击后跳转的链接, 不少于1字节, 不多于2048字节
-		Content string `json:"content"` // 菜单显示内容, 不少于1字节, 不多于1024字节
-	} `json:"view"`
-}
+type MenuView = sendmsg.MenuView
 
 // MenuMiniProgram 小程序菜单
-type MenuMiniProgram struct {
-	Type        string `json:"type"` // 菜单类型: miniprogram 小程序菜单
-	MiniProgram struct {
-		AppID    string `json:"appid"`    // 小程序appid, 不少于1字节, 不多于32字节
-		PagePath string `json:"pagepath"` // 点击后进入的小程序页面, 不少于1字节, 不多于1024字节
-		Content  string `json:"content"`  // 菜单显示内容, 不少于1字节, 不多于1024字节
-	} `json:"miniprogram"`
-}
+type MenuMiniProgram = sendmsg.MenuMiniProgram
+
+// MenuText 文本菜单
+type MenuText = sendmsg.MenuText
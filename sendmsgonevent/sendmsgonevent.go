@@ -0,0 +1,139 @@
+package sendmsgonevent
+
+import "github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+
+// 消息类型
+const (
+	MsgTypeText = sendmsg.MsgTypeText // 文本消息
+	MsgTypeMenu = sendmsg.MsgTypeMenu // 菜单消息
+)
+
+// 菜单类型
+const (
+	MenuTypeClick       = sendmsg.MenuTypeClick       // 回复菜单
+	MenuTypeView        = sendmsg.MenuTypeView        // 超链接菜单
+	MenuTypeMiniProgram = sendmsg.MenuTypeMiniProgram // 小程序菜单
+	MenuTypeText        = sendmsg.MenuTypeText        // 文本菜单
+)
+
+// NewText 创建文本消息
+func NewText(code, content string) Text {
+	return Text{Message: Message{Code: code}, MsgType: MsgTypeText, Text: sendmsg.TextContent{Content: content}}
+}
+
+// NewMenu 创建菜单消息
+func NewMenu(code, headContent string, list MenuList, tailContent string) Menu {
+	return fromMenu(code, sendmsg.NewMenu("", "", headContent, list, tailContent))
+}
+
+// fromMenu 将发送消息的菜单消息转换为事件响应消息
+func fromMenu(code string, menu sendmsg.Menu) Menu {
+	return Menu{Message: Message{Code: code, MsgID: menu.MsgID}, MsgType: MsgTypeMenu, MsgMenu: menu.MsgMenu}
+}
+
+// NewMenuClick 创建回复菜单项
+func NewMenuClick(id, content string) MenuClick {
+	return sendmsg.NewMenuClick(id, content)
+}
+
+// NewMenuView 创建超链接菜单项
+func NewMenuView(url, content string) MenuView {
+	return sendmsg.NewMenuView(url, content)
+}
+
+// NewMenuMiniProgram 创建小程序菜单项
+func NewMenuMiniProgram(appID, pagePath, content string) MenuMiniProgram {
+	return sendmsg.NewMenuMiniProgram(appID, pagePath, content)
+}
+
+// NewMenuText 创建文本菜单项，noNewline为true时内容后面不换行
+func NewMenuText(content string, noNewline bool) MenuText {
+	return sendmsg.NewMenuText(content, noNewline)
+}
+
+// MenuBuilder 菜单消息构建器，菜单内容由 sendmsg.MenuBuilder 构建
+type MenuBuilder struct {
+	code    string
+	builder *sendmsg.MenuBuilder
+}
+
+// NewMenuBuilder 创建菜单消息构建器
+func NewMenuBuilder(code string) *MenuBuilder {
+	return &MenuBuilder{code: code, builder: sendmsg.NewMenuBuilder("", "")}
+}
+
+// MsgID 指定消息ID
+func (r *MenuBuilder) MsgID(msgID string) *MenuBuilder {
+	r.builder.MsgID(msgID)
+	return r
+}
+
+// Head 设置起始文本
+func (r *MenuBuilder) Head(content string) *MenuBuilder {
+	r.builder.Head(content)
+	return r
+}
+
+// Tail 设置结束文本
+func (r *MenuBuilder) Tail(content string) *MenuBuilder {
+	r.builder.Tail(content)
+	return r
+}
+
+// Click 添加回复菜单项
+func (r *MenuBuilder) Click(id, content string) *MenuBuilder {
+	r.builder.Click(id, content)
+	return r
+}
+
+// View 添加超链接菜单项
+func (r *MenuBuilder) View(url, content string) *MenuBuilder {
+	r.builder.View(url, content)
+	return r
+}
+
+// MiniProgram 添加小程序菜单项
+func (r *MenuBuilder) MiniProgram(appID, pagePath, content string) *MenuBuilder {
+	r.builder.MiniProgram(appID, pagePath, content)
+	return r
+}
+
+// Text 添加文本菜单项
+func (r *MenuBuilder) Text(content string, noNewline bool) *MenuBuilder {
+	r.builder.Text(content, noNewline)
+	return r
+}
+
+// Build 生成菜单消息，菜单项超过10个时发送会返回 sendmsg.ErrMenuItemsExceeded
+func (r *MenuBuilder) Build() Menu {
+	return fromMenu(r.code, r.builder.Build())
+}
+
+func (r Message) validate() error {
+	if err := sendmsg.ValidateLength("code", r.Code, 1, 0); err != nil {
+		return err
+	}
+	return sendmsg.ValidateMsgID(r.MsgID)
+}
+
+// Validate 校验文本消息
+func (r Text) Validate() error {
+	if err := r.Message.validate(); err != nil {
+		return err
+	}
+	if err := sendmsg.ValidateMsgType(r.MsgType, MsgTypeText); err != nil {
+		return err
+	}
+	return r.Text.Validate()
+}
+
+// Validate 校验菜单消息
+func (r Menu) Validate() error {
+	if err := r.Message.validate(); err != nil {
+		return err
+	}
+	if err := sendmsg.ValidateMsgType(r.MsgType, MsgTypeMenu); err != nil {
+		return err
+	}
+	return r.MsgMenu.Validate()
+}
@@ -0,0 +1,93 @@
+package sendmsgonevent
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message interface{}
+		want    string
+	}{
+		{
+			name:    "text",
+			message: NewText("code", "hello"),
+			want:    `{"code":"code","msgid":"","msgtype":"text","text":{"content":"hello"}}`,
+		},
+		{
+			name:    "menu",
+			message: NewMenu("code", "head", MenuList{NewMenuClick("1", "yes"), NewMenuText("tip", false)}, "tail"),
+			want:    `{"code":"code","msgid":"","msgtype":"msgmenu","msgmenu":{"head_content":"head","list":[{"type":"click","click":{"id":"1","content":"yes"}},{"type":"text","text":{"content":"tip","no_newline":0}}],"tail_content":"tail"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenuBuilder(t *testing.T) {
+	menu := NewMenuBuilder("code").
+		MsgID("m1").
+		Head("head").
+		Click("1", "yes").
+		View("https://example.com", "link").
+		MiniProgram("wx1", "index", "open").
+		Text("tip", true).
+		Tail("tail").
+		Build()
+
+	want := NewMenu("code", "head", MenuList{
+		NewMenuClick("1", "yes"),
+		NewMenuView("https://example.com", "link"),
+		NewMenuMiniProgram("wx1", "index", "open"),
+		NewMenuText("tip", true),
+	}, "tail")
+	want.MsgID = "m1"
+	if !reflect.DeepEqual(menu, want) {
+		t.Fatalf("Build() = %+v, want %+v", menu, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	wrongType := NewText("code", "hello")
+	wrongType.MsgType = MsgTypeMenu
+	tests := []struct {
+		name      string
+		message   sendmsg.Validator
+		wantField string
+	}{
+		{name: "text", message: NewText("code", "hello")},
+		{name: "text without code", message: NewText("", "hello"), wantField: "code"},
+		{name: "empty text", message: NewText("code", ""), wantField: "text.content"},
+		{name: "wrong msgtype", message: wrongType, wantField: "msgtype"},
+		{name: "menu", message: NewMenu("code", "head", MenuList{NewMenuClick("1", "yes")}, "")},
+		{name: "menu item without id", message: NewMenu("code", "", MenuList{NewMenuClick("", "yes")}, ""), wantField: "click.id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() err = %v, want nil", err)
+				}
+				return
+			}
+			if validationErr, ok := err.(sendmsg.ValidationError); !ok || validationErr.Field != tt.wantField {
+				t.Fatalf("Validate() err = %v, want ValidationError on %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+// sendPayload 发送消息请求内容及其公共字段
+type sendPayload struct {
+	data     json.RawMessage
+	ToUser   string `json:"touser"`    // 接收消息的客户UserID
+	OpenKFID string `json:"open_kfid"` // 发送消息的客服帐号ID
+	MsgID    string `json:"msgid"`     // 消息ID
+	MsgType  string `json:"msgtype"`   // 消息类型
+	Code     string `json:"code"`      // 事件响应消息对应的code
+}
+
+// newSendPayload 校验并序列化发送消息请求参数，解析公共字段
+func newSendPayload(options interface{}) (payload sendPayload, err error) {
+	if validator, ok := options.(sendmsg.Validator); ok {
+		if err = validator.Validate(); err != nil {
+			return payload, err
+		}
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return payload, err
+	}
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return payload, err
+	}
+	payload.data = data
+	return payload, nil
+}
+
+// withMsgID 指定请求内容中的消息ID
+func (r sendPayload) withMsgID(msgID string) (sendPayload, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(r.data, &fields); err != nil {
+		return r, err
+	}
+	value, err := json.Marshal(msgID)
+	if err != nil {
+		return r, err
+	}
+	fields["msgid"] = value
+	if r.data, err = json.Marshal(fields); err != nil {
+		return r, err
+	}
+	r.MsgID = msgID
+	return r, nil
+}
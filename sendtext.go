@@ -0,0 +1,90 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"fmt"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+)
+
+// 分段序号预留的字节数，如 "(10/10) "
+const textNumberReserve = 16
+
+// SendTextOptions 发送长文本消息参数
+type SendTextOptions struct {
+	ToUser     string                                             // 接收消息的客户UserID
+	OpenKFID   string                                             // 发送消息的客服帐号ID
+	Content    string                                             // 文本内容，超过2048字节时自动拆分为多条消息
+	IsNumbered bool                                               // 是否在每段前添加序号，如 (1/3)
+	Fallback   func(options SendTextOptions) (interface{}, error) // 拆分后的条数超过剩余额度时，用于生成替代消息（如链接或文件），为空时返回 SDKQuotaExceeded
+}
+
+// SendText 发送文本消息，超长内容按段落和句子拆分为多条发送
+// 拆分后的条数会与客户48小时内剩余的可接收条数比较（未开启 IsEnableQuota 或无记录时按5条计算），超出时改为发送 Fallback 生成的消息
+func (r *Client) SendText(options SendTextOptions) (list []SendMsgSchema, err error) {
+	maxBytes := sendmsg.MaxTextBytes
+	if options.IsNumbered {
+		maxBytes -= textNumberReserve
+	}
+	parts := sendmsg.SplitText(options.Content, maxBytes)
+
+	remaining := quotaMaxMsgCount
+	if r.isEnableQuota {
+		quota, err := r.QuotaGet(options.OpenKFID, options.ToUser)
+		if err != nil {
+			return nil, err
+		}
+		if quota.IsTracked {
+			remaining = quota.Remaining
+		}
+	}
+	if len(parts) > remaining {
+		if options.Fallback == nil {
+			return nil, NewSDKErr(50007)
+		}
+		message, err := options.Fallback(options)
+		if err != nil {
+			return nil, err
+		}
+		info, err := r.SendMsg(message)
+		if err != nil {
+			return nil, err
+		}
+		return []SendMsgSchema{info}, nil
+	}
+
+	list = make([]SendMsgSchema, 0, len(parts))
+	for index, part := range parts {
+		if options.IsNumbered && len(parts) > 1 {
+			part = fmt.Sprintf("(%d/%d) %s", index+1, len(parts), part)
+		}
+		info, err := r.SendMsg(sendmsg.NewText(options.ToUser, options.OpenKFID, part))
+		if err != nil {
+			return list, err
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// TextFileFallback 将完整文本上传为文件发送，可作为 SendTextOptions.Fallback 使用
+func (r *Client) TextFileFallback(fileName string) func(options SendTextOptions) (interface{}, error) {
+	return func(options SendTextOptions) (interface{}, error) {
+		body := []byte(options.Content)
+		info, err := r.MediaOriginUpload(fileName, "file", len(body), body)
+		if err != nil {
+			return nil, err
+		}
+		return sendmsg.NewFile(options.ToUser, options.OpenKFID, info.MediaID), nil
+	}
+}
+
+// TextLinkFallback 发送指向完整文本的图文链接，url 由调用方根据内容生成（如保存后返回的访问地址），可作为 SendTextOptions.Fallback 使用
+func TextLinkFallback(title, desc, thumbMediaID string, url func(content string) (string, error)) func(options SendTextOptions) (interface{}, error) {
+	return func(options SendTextOptions) (interface{}, error) {
+		link, err := url(options.Content)
+		if err != nil {
+			return nil, err
+		}
+		return sendmsg.NewLink(options.ToUser, options.OpenKFID, title, desc, link, thumbMediaID), nil
+	}
+}
@@ -0,0 +1,149 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// textServer 模拟发送消息和素材上传接口，记录发送的消息类型和内容
+type textServer struct {
+	mutex    sync.Mutex
+	msgTypes []string
+	contents []string // 文本消息内容，文件消息为media_id，链接消息为url
+}
+
+func newTextServer(t *testing.T) *textServer {
+	server := &textServer{}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if strings.HasSuffix(req.URL.Path, "/media/upload") {
+			_, _ = w.Write([]byte(`{"errcode":0,"type":"file","media_id":"file-1"}`))
+			return
+		}
+		body := struct {
+			MsgID   string `json:"msgid"`
+			MsgType string `json:"msgtype"`
+			Text    struct {
+				Content string `json:"content"`
+			} `json:"text"`
+			File struct {
+				MediaID string `json:"media_id"`
+			} `json:"file"`
+			Link struct {
+				URL string `json:"url"`
+			} `json:"link"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		server.msgTypes = append(server.msgTypes, body.MsgType)
+		server.contents = append(server.contents, body.Text.Content+body.File.MediaID+body.Link.URL)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body.MsgID})
+	})
+	return server
+}
+
+// paragraphs 生成 n 个段落，每段单独拆分为一条消息
+func paragraphs(n int) string {
+	list := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		list = append(list, strings.Repeat(string(rune('a'+i)), 1500))
+	}
+	return strings.Join(list, "\n\n")
+}
+
+func TestSendTextNumbering(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		isNumbered bool
+		wantPrefix []string
+	}{
+		{name: "numbered parts", content: paragraphs(3), isNumbered: true, wantPrefix: []string{"(1/3) a", "(2/3) b", "(3/3) c"}},
+		{name: "not numbered", content: paragraphs(2), wantPrefix: []string{"a", "b"}},
+		{name: "single part is not numbered", content: "hello", isNumbered: true, wantPrefix: []string{"hello"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTextServer(t)
+			client := newTestClient(t, Options{CorpID: "corp"})
+
+			list, err := client.SendText(SendTextOptions{ToUser: "user", OpenKFID: "kf", Content: tt.content, IsNumbered: tt.isNumbered})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != len(tt.wantPrefix) || len(server.contents) != len(tt.wantPrefix) {
+				t.Fatalf("sent %d messages, want %d", len(server.contents), len(tt.wantPrefix))
+			}
+			for index, content := range server.contents {
+				if !strings.HasPrefix(content, tt.wantPrefix[index]) {
+					t.Fatalf("part %d = %.12q, want prefix %q", index, content, tt.wantPrefix[index])
+				}
+				if len(content) > 2048 {
+					t.Fatalf("part %d has %d bytes", index, len(content))
+				}
+			}
+		})
+	}
+}
+
+func TestSendTextQuota(t *testing.T) {
+	linkFallback := TextLinkFallback("title", "desc", "thumb", func(content string) (string, error) {
+		return "https://example.com/text", nil
+	})
+	tests := []struct {
+		name         string
+		enableQuota  bool
+		sentCount    int // 开启额度记录时客户已接收的消息数，小于0时不写入记录
+		parts        int
+		fallback     string
+		wantErr      error
+		wantMsgTypes []string
+	}{
+		{name: "tracking off allows 5 parts", parts: 5, wantMsgTypes: []string{"text", "text", "text", "text", "text"}},
+		{name: "tracking off over 5 parts without fallback", parts: 6, wantErr: SDKQuotaExceeded},
+		{name: "tracking off over 5 parts uses link", parts: 6, fallback: "link", wantMsgTypes: []string{"link"}},
+		{name: "no record is treated as 5", enableQuota: true, sentCount: -1, parts: 6, fallback: "link", wantMsgTypes: []string{"link"}},
+		{name: "within remaining quota", enableQuota: true, sentCount: 3, parts: 2, wantMsgTypes: []string{"text", "text"}},
+		{name: "over remaining quota uses file", enableQuota: true, sentCount: 3, parts: 3, fallback: "file", wantMsgTypes: []string{"file"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTextServer(t)
+			client := newTestClient(t, Options{CorpID: "corp", IsEnableQuota: tt.enableQuota})
+			if tt.enableQuota && tt.sentCount >= 0 {
+				setQuota(t, client, "kf", "user", time.Now(), tt.sentCount)
+			}
+			options := SendTextOptions{ToUser: "user", OpenKFID: "kf", Content: paragraphs(tt.parts)}
+			switch tt.fallback {
+			case "link":
+				options.Fallback = linkFallback
+			case "file":
+				options.Fallback = client.TextFileFallback("text.txt")
+			}
+
+			_, err := client.SendText(options)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !equalStrings(server.msgTypes, tt.wantMsgTypes) {
+				t.Fatalf("msgtypes = %v, want %v", server.msgTypes, tt.wantMsgTypes)
+			}
+			switch tt.fallback {
+			case "link":
+				if server.contents[0] != "https://example.com/text" {
+					t.Fatalf("link url = %q", server.contents[0])
+				}
+			case "file":
+				if server.contents[0] != "file-1" {
+					t.Fatalf("file media_id = %q", server.contents[0])
+				}
+			}
+		})
+	}
+}
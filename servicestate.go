@@ -69,5 +69,6 @@ func (r *Client) ServiceStateTrans(options ServiceStateTransOptions) (info Servi
 	if info.ErrCode != 0 {
 		return info, NewSDKErr(info.ErrCode, info.ErrMsg)
 	}
-	return info, nil
+	//记录code对应的会话，发送事件响应消息后可按客服帐号和客户查询会话记录
+	return info, r.saveEventCode(info.MsgCode, options.OpenKFID, options.ExternalUserID)
 }
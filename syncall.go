@@ -0,0 +1,131 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"sync"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/cache"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// CursorStore 消息拉取游标存储
+type CursorStore interface {
+	GetCursor(key string) (string, error)
+	SetCursor(key, cursor string) error
+}
+
+// cacheCursorStore 基于缓存的游标存储，游标永不过期
+type cacheCursorStore struct {
+	cache cache.Cache
+}
+
+// NewCacheCursorStore 初始化基于缓存的游标存储
+func NewCacheCursorStore(c cache.Cache) CursorStore {
+	return &cacheCursorStore{cache: c}
+}
+
+func (r *cacheCursorStore) GetCursor(key string) (string, error) {
+	return r.cache.Get(key)
+}
+
+func (r *cacheCursorStore) SetCursor(key, cursor string) error {
+	return r.cache.Set(key, cursor, 0)
+}
+
+// SyncHandler 消息处理函数，返回错误时不会保存本页游标，下次拉取将从本页重新开始
+type SyncHandler func(msgList []syncmsg.Message) error
+
+// SyncAll 从已保存的游标开始循环拉取消息直至没有更多数据，每页消息处理成功后保存游标，重启后可从上次的位置继续拉取
+// 会话记录保存、额度跟踪和拉取回调在 handler 处理成功后执行，handler 返回错误时均不会执行，重试时也不会重复执行
+// 开启消息去重后，已处理过的消息不会再交给handler
+// options.Cursor 为空时使用已保存的游标
+func (r *Client) SyncAll(options SyncMsgOptions, handler SyncHandler) error {
+	key := r.cursorKey(options.OpenKFID)
+	lock := r.syncLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cursor, err := r.loadCursor(key, options.Cursor)
+	if err != nil {
+		return err
+	}
+	options.Cursor = cursor
+
+	for {
+		_, hasMore, err := r.syncPage(key, &options, handler)
+		if err != nil {
+			return err
+		}
+		if !hasMore {
+			return nil
+		}
+	}
+}
+
+// syncPage 拉取一页消息，处理成功后保存游标并更新 options.Cursor，返回本页消息数和是否需继续拉取
+// 调用方需持有游标锁
+func (r *Client) syncPage(key string, options *SyncMsgOptions, handler SyncHandler) (int, bool, error) {
+	info, err := r.fetchMsg(*options)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(info.MsgList) > 0 {
+		if err = handler(info.MsgList); err != nil {
+			return 0, false, err
+		}
+	}
+	if err = r.commitMsgList(key, info.MsgList, info.NextCursor); err != nil {
+		return 0, false, err
+	}
+	if info.NextCursor != "" {
+		options.Cursor = info.NextCursor
+	}
+	//has_more为1时msg_list可能为空，需继续拉取
+	return len(info.MsgList), info.HasMore == 1 && info.NextCursor != "", nil
+}
+
+// loadCursor 未指定游标时读取已保存的游标
+func (r *Client) loadCursor(key, cursor string) (string, error) {
+	if cursor != "" {
+		return cursor, nil
+	}
+	cursor, err := r.cursorStore.GetCursor(key)
+	if err != nil {
+		return "", NewSDKErr(50002)
+	}
+	return cursor, nil
+}
+
+// filterMsgList 过滤已处理过的消息
+func (r *Client) filterMsgList(msgList []syncmsg.Message) ([]syncmsg.Message, error) {
+	if r.dedup == nil {
+		return msgList, nil
+	}
+	return r.dedup.Filter(msgList)
+}
+
+// commitMsgList 完成已处理消息的会话记录保存、额度跟踪、去重记录和拉取回调，并保存游标
+func (r *Client) commitMsgList(key string, msgList []syncmsg.Message, nextCursor string) error {
+	if err := r.completeMsgList(msgList); err != nil {
+		return err
+	}
+	if nextCursor != "" {
+		if err := r.cursorStore.SetCursor(key, nextCursor); err != nil {
+			return NewSDKErr(50002)
+		}
+	}
+	return nil
+}
+
+// cursorKey 游标存储键，指定客服帐号时每个帐号单独保存游标
+func (r *Client) cursorKey(openKFID string) string {
+	if openKFID == "" {
+		return "wechat:kf:cursor:" + r.corpID
+	}
+	return "wechat:kf:cursor:" + r.corpID + ":" + openKFID
+}
+
+// syncLock 获取游标对应的锁，保证同一游标不会被并发拉取
+func (r *Client) syncLock(key string) *sync.Mutex {
+	lock, _ := r.syncLocks.LoadOrStore(key, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
@@ -0,0 +1,290 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// syncPageFixture 模拟拉取消息接口返回的一页数据，Cursor 为请求该页时携带的游标
+type syncPageFixture struct {
+	Cursor     string
+	NextCursor string
+	HasMore    uint32
+	MsgIDs     []string
+}
+
+// syncServer 模拟拉取消息接口，记录每次请求的游标
+type syncServer struct {
+	mutex   sync.Mutex
+	pages   []syncPageFixture
+	cursors []string
+}
+
+func newSyncServer(t *testing.T, pages ...syncPageFixture) *syncServer {
+	server := &syncServer{pages: pages}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		var options SyncMsgOptions
+		if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+			t.Error(err)
+		}
+		server.mutex.Lock()
+		server.cursors = append(server.cursors, options.Cursor)
+		server.mutex.Unlock()
+		for _, page := range server.pages {
+			if page.Cursor != options.Cursor {
+				continue
+			}
+			msgList := make([]map[string]interface{}, 0, len(page.MsgIDs))
+			for _, msgID := range page.MsgIDs {
+				msgList = append(msgList, map[string]interface{}{
+					"msgid":           msgID,
+					"open_kfid":       "kf",
+					"external_userid": "user",
+					"send_time":       1,
+					"origin":          3,
+					"msgtype":         "text",
+					"text":            map[string]string{"content": msgID},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"errcode":     0,
+				"next_cursor": page.NextCursor,
+				"has_more":    page.HasMore,
+				"msg_list":    msgList,
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 95007, "errmsg": "invalid cursor"})
+	})
+	return server
+}
+
+func (r *syncServer) requestedCursors() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.cursors...)
+}
+
+func TestSyncMsgListenersSkipDuplicates(t *testing.T) {
+	newSyncServer(t, syncPageFixture{NextCursor: "c1", MsgIDs: []string{"a", "b"}})
+	client := newTestClient(t, Options{CorpID: "corp", DedupExpireTime: time.Hour})
+
+	var seen []string
+	client.addSyncListener(func(msgList []syncmsg.Message) {
+		seen = append(seen, msgIDs(msgList)...)
+	})
+	if err := client.dedup.Mark([]syncmsg.Message{{MsgID: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := client.SyncMsg(SyncMsgOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msgIDs(info.MsgList); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("MsgList = %v, want [b]", got)
+	}
+	if len(seen) != 1 || seen[0] != "b" {
+		t.Fatalf("listener saw %v, want [b]", seen)
+	}
+}
+
+func TestSyncMsgTwiceOnSamePage(t *testing.T) {
+	newSyncServer(t, syncPageFixture{NextCursor: "c1", MsgIDs: []string{"a", "b"}})
+	client := newTestClient(t, Options{CorpID: "corp", DedupExpireTime: time.Hour})
+
+	var seen []string
+	client.addSyncListener(func(msgList []syncmsg.Message) {
+		seen = append(seen, msgIDs(msgList)...)
+	})
+	first, err := client.SyncMsg(SyncMsgOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.SyncMsg(SyncMsgOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msgIDs(first.MsgList); !equalStrings(got, []string{"a", "b"}) {
+		t.Fatalf("first MsgList = %v, want [a b]", got)
+	}
+	if got := msgIDs(second.MsgList); len(got) != 0 {
+		t.Fatalf("second MsgList = %v, want empty", got)
+	}
+	if !equalStrings(seen, []string{"a", "b"}) {
+		t.Fatalf("listener saw %v, want [a b] once", seen)
+	}
+}
+
+func TestSyncAllSideEffectsAfterHandler(t *testing.T) {
+	newSyncServer(t, syncPageFixture{NextCursor: "c1", MsgIDs: []string{"a"}})
+	client := newTestClient(t, Options{CorpID: "corp", DedupExpireTime: time.Hour})
+
+	var seen []string
+	client.addSyncListener(func(msgList []syncmsg.Message) {
+		seen = append(seen, msgIDs(msgList)...)
+	})
+	err := client.SyncAll(SyncMsgOptions{}, func(msgList []syncmsg.Message) error {
+		return SDKUnknownError
+	})
+	if err != SDKUnknownError {
+		t.Fatalf("SyncAll() err = %v, want %v", err, SDKUnknownError)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("listener saw %v before handler succeeded", seen)
+	}
+
+	//重试时消息未被记录为已处理，会再次交给handler，回调只触发一次
+	var handled []string
+	err = client.SyncAll(SyncMsgOptions{}, func(msgList []syncmsg.Message) error {
+		handled = append(handled, msgIDs(msgList)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(handled, []string{"a"}) {
+		t.Fatalf("handled = %v, want [a]", handled)
+	}
+	if !equalStrings(seen, []string{"a"}) {
+		t.Fatalf("listener saw %v, want [a]", seen)
+	}
+}
+
+func TestSyncAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		pages       []syncPageFixture
+		saved       string
+		handlerErr  error
+		wantErr     bool
+		wantMsgIDs  []string
+		wantCursors []string
+		wantSaved   string
+	}{
+		{
+			name: "follows has_more",
+			pages: []syncPageFixture{
+				{NextCursor: "c1", HasMore: 1, MsgIDs: []string{"a"}},
+				{Cursor: "c1", NextCursor: "c2", MsgIDs: []string{"b"}},
+			},
+			wantMsgIDs:  []string{"a", "b"},
+			wantCursors: []string{"", "c1"},
+			wantSaved:   "c2",
+		},
+		{
+			name: "continues past empty pages with has_more",
+			pages: []syncPageFixture{
+				{NextCursor: "c1", HasMore: 1},
+				{Cursor: "c1", NextCursor: "c2", HasMore: 1},
+				{Cursor: "c2", NextCursor: "c3", MsgIDs: []string{"a"}},
+			},
+			wantMsgIDs:  []string{"a"},
+			wantCursors: []string{"", "c1", "c2"},
+			wantSaved:   "c3",
+		},
+		{
+			name:        "resumes from saved cursor",
+			saved:       "c5",
+			pages:       []syncPageFixture{{Cursor: "c5", NextCursor: "c6", MsgIDs: []string{"f"}}},
+			wantMsgIDs:  []string{"f"},
+			wantCursors: []string{"c5"},
+			wantSaved:   "c6",
+		},
+		{
+			name:        "keeps cursor when handler fails",
+			saved:       "c5",
+			pages:       []syncPageFixture{{Cursor: "c5", NextCursor: "c6", MsgIDs: []string{"f"}}},
+			handlerErr:  SDKUnknownError,
+			wantErr:     true,
+			wantMsgIDs:  []string{"f"},
+			wantCursors: []string{"c5"},
+			wantSaved:   "c5",
+		},
+		{
+			name:        "keeps cursor when next_cursor is empty",
+			saved:       "c5",
+			pages:       []syncPageFixture{{Cursor: "c5", HasMore: 1}},
+			wantCursors: []string{"c5"},
+			wantSaved:   "c5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSyncServer(t, tt.pages...)
+			client := newTestClient(t, Options{CorpID: "corp"})
+			if tt.saved != "" {
+				_ = client.cursorStore.SetCursor(client.cursorKey(""), tt.saved)
+			}
+
+			var got []string
+			err := client.SyncAll(SyncMsgOptions{}, func(msgList []syncmsg.Message) error {
+				got = append(got, msgIDs(msgList)...)
+				return tt.handlerErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SyncAll() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !equalStrings(got, tt.wantMsgIDs) {
+				t.Fatalf("handled = %v, want %v", got, tt.wantMsgIDs)
+			}
+			if cursors := server.requestedCursors(); !equalStrings(cursors, tt.wantCursors) {
+				t.Fatalf("requested cursors = %v, want %v", cursors, tt.wantCursors)
+			}
+			if saved, _ := client.cursorStore.GetCursor(client.cursorKey("")); saved != tt.wantSaved {
+				t.Fatalf("saved cursor = %q, want %q", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestSyncAllSkipsProcessedMessages(t *testing.T) {
+	newSyncServer(t, syncPageFixture{NextCursor: "c1", MsgIDs: []string{"a", "b"}})
+	client := newTestClient(t, Options{CorpID: "corp", DedupExpireTime: time.Hour})
+
+	var got []string
+	handler := func(msgList []syncmsg.Message) error {
+		got = append(got, msgIDs(msgList)...)
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		//第二次从头拉取时同一页消息已处理过
+		if err := client.SyncAll(SyncMsgOptions{Cursor: ""}, handler); err != nil {
+			t.Fatal(err)
+		}
+		_ = client.cursorStore.SetCursor(client.cursorKey(""), "")
+	}
+	if !equalStrings(got, []string{"a", "b"}) {
+		t.Fatalf("handled = %v, want [a b] once", got)
+	}
+	if stats := client.DedupStats(); stats.Dropped != 2 {
+		t.Fatalf("Dropped = %d, want 2", stats.Dropped)
+	}
+}
+
+func TestCursorKey(t *testing.T) {
+	client := newTestClient(t, Options{CorpID: "corp"})
+	if got := client.cursorKey(""); got != "wechat:kf:cursor:corp" {
+		t.Fatalf("cursorKey() = %q", got)
+	}
+	if got := client.cursorKey("kf"); got != "wechat:kf:cursor:corp:kf" {
+		t.Fatalf("cursorKey(kf) = %q", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
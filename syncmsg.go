@@ -2,7 +2,6 @@ package WeChatCustomerServiceSDK
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
 	"github.com/NICEXAI/WeChatCustomerServiceSDK/util"
@@ -15,18 +14,20 @@ const (
 
 // SyncMsgOptions 获取消息查询参数
 type SyncMsgOptions struct {
-	Cursor string `json:"cursor"` // 上一次调用时返回的next_cursor，第一次拉取可以不填, 不多于64字节
-	Token  string `json:"token"`  // 回调事件返回的token字段，10分钟内有效；可不填，如果不填接口有严格的频率限制, 不多于128字节
-	Limit  uint   `json:"limit"`  // 期望请求的数据量，默认值和最大值都为1000, 注意：可能会出现返回条数少于limit的情况，需结合返回的has_more字段判断是否继续请求。
+	Cursor      string `json:"cursor"`                 // 上一次调用时返回的next_cursor，第一次拉取可以不填, 不多于64字节
+	Token       string `json:"token"`                  // 回调事件返回的token字段，10分钟内有效；可不填，如果不填接口有严格的频率限制, 不多于128字节
+	Limit       uint   `json:"limit"`                  // 期望请求的数据量，默认值和最大值都为1000, 注意：可能会出现返回条数少于limit的情况，需结合返回的has_more字段判断是否继续请求。
+	OpenKFID    string `json:"open_kfid,omitempty"`    // 指定拉取某个客服帐号的消息，否则默认返回有权限的客服帐号的消息。当客服帐号较多，建议按客服帐号分别拉取
+	VoiceFormat uint32 `json:"voice_format,omitempty"` // 语音消息类型，0-Amr 1-Silk，默认0。可通过该参数控制返回的语音格式，开发者可按需选择自己程序支持的一种格式
 }
 
-// SyncMsgSchema 获取消息查询响应内容
+// syncMsgSchema 获取消息查询原始响应内容
 type syncMsgSchema struct {
-	ErrCode    int32                    `json:"errcode"`     // 返回码
-	ErrMsg     string                   `json:"errmsg"`      // 错误码描述
-	NextCursor string                   `json:"next_cursor"` // 下次调用带上该值，则从当前的位置继续往后拉，以实现增量拉取。强烈建议对改该字段入库保存，每次请求读取带上，请求结束后更新。避免因意外丢，导致必须从头开始拉取，引起消息延迟。
-	HasMore    uint32                   `json:"has_more"`    // 是否还有更多数据。0-否；1-是。不能通过判断msg_list是否空来停止拉取，可能会出现has_more为1，而msg_list为空的情况
-	MsgList    []map[string]interface{} `json:"msg_list"`    // 消息列表
+	ErrCode    int32             `json:"errcode"`     // 返回码
+	ErrMsg     string            `json:"errmsg"`      // 错误码描述
+	NextCursor string            `json:"next_cursor"` // 下次调用带上该值，则从当前的位置继续往后拉，以实现增量拉取。强烈建议对改该字段入库保存，每次请求读取带上，请求结束后更新。避免因意外丢，导致必须从头开始拉取，引起消息延迟。
+	HasMore    uint32            `json:"has_more"`    // 是否还有更多数据。0-否；1-是。不能通过判断msg_list是否空来停止拉取，可能会出现has_more为1，而msg_list为空的情况
+	MsgList    []json.RawMessage `json:"msg_list"`    // 消息列表，保留原始内容，按需解析
 }
 
 // SyncMsgSchema 获取消息查询响应内容
@@ -39,56 +40,41 @@ type SyncMsgSchema struct {
 }
 
 // SyncMsg 获取消息
+// 消息列表中的每条消息只解析公共字段，原始内容保存在 OriginData 中，可通过 Message.Decode 解析为具体的消息类型
+// 开启消息去重后，返回的消息列表中不包含已处理过的消息，返回前即记录为已处理，重复调用不会再次返回同一条消息
 func (r *Client) SyncMsg(options SyncMsgOptions) (info SyncMsgSchema, err error) {
+	if info, err = r.fetchMsg(options); err != nil {
+		return info, err
+	}
+	if err = r.completeMsgList(info.MsgList); err != nil {
+		return SyncMsgSchema{}, err
+	}
+	return info, nil
+}
+
+// fetchMsg 拉取并解析一页消息，过滤已处理过的消息，不触发会话记录、额度跟踪和拉取回调
+func (r *Client) fetchMsg(options SyncMsgOptions) (info SyncMsgSchema, err error) {
 	data, err := util.HttpPost(fmt.Sprintf(syncMsgAddr, r.accessToken), options)
 	if err != nil {
 		return info, err
 	}
-	fmt.Println(string(data))
 	originInfo := syncMsgSchema{}
 	if err = json.Unmarshal(data, &originInfo); err != nil {
 		return info, err
 	}
 	if originInfo.ErrCode != 0 {
-		return info, errors.New(originInfo.ErrMsg)
+		return info, NewSDKErr(int64(originInfo.ErrCode), originInfo.ErrMsg)
 	}
-	msgList := make([]syncmsg.Message, 0)
-	if len(originInfo.MsgList) > 0 {
-		for _, msg := range originInfo.MsgList {
-			newMsg := syncmsg.Message{}
-			if val, ok := msg["msgid"].(string); ok {
-				newMsg.MsgID = val
-			}
-			if val, ok := msg["open_kfid"].(string); ok {
-				newMsg.OpenKFID = val
-			}
-			if val, ok := msg["external_userid"].(string); ok {
-				newMsg.ExternalUserID = val
-			}
-			if val, ok := msg["send_time"].(float64); ok {
-				newMsg.SendTime = uint64(val)
-			}
-			if val, ok := msg["origin"].(float64); ok {
-				newMsg.Origin = uint32(val)
-			}
-
-			if val, ok := msg["msgtype"].(string); ok {
-				newMsg.MsgType = val
-			}
-			if newMsg.MsgType == "event" {
-				if event, ok := msg["event"].(map[string]interface{}); ok {
-					if eType, ok := event["event_type"].(string); ok {
-						newMsg.EventType = eType
-					}
-				}
-			}
-			originData, err := json.Marshal(msg)
-			if err != nil {
-				return info, err
-			}
-			newMsg.OriginData = originData
-			msgList = append(msgList, newMsg)
+	msgList := make([]syncmsg.Message, 0, len(originInfo.MsgList))
+	for _, raw := range originInfo.MsgList {
+		newMsg, err := syncmsg.NewMessage(raw)
+		if err != nil {
+			return info, err
 		}
+		msgList = append(msgList, newMsg)
+	}
+	if msgList, err = r.filterMsgList(msgList); err != nil {
+		return info, err
 	}
 	return SyncMsgSchema{
 		ErrCode:    originInfo.ErrCode,
@@ -98,3 +84,43 @@ func (r *Client) SyncMsg(options SyncMsgOptions) (info SyncMsgSchema, err error)
 		MsgList:    msgList,
 	}, nil
 }
+
+// completeMsgList 保存会话记录、跟踪发送额度并记录已处理的消息，最后触发拉取回调
+// 记录已处理在拉取回调之前，重复投递的消息不会再次触发欢迎语、发送失败关联等回调
+func (r *Client) completeMsgList(msgList []syncmsg.Message) error {
+	if len(msgList) == 0 {
+		return nil
+	}
+	if err := r.saveSyncedMessages(msgList); err != nil {
+		return err
+	}
+	if err := r.trackSyncedQuota(msgList); err != nil {
+		return err
+	}
+	if r.dedup != nil {
+		if err := r.dedup.Mark(msgList); err != nil {
+			return err
+		}
+	}
+	r.notifySyncListeners(msgList)
+	return nil
+}
+
+// addSyncListener 注册拉取消息后的回调，用于关联发送失败等事件
+func (r *Client) addSyncListener(listener func(msgList []syncmsg.Message)) {
+	r.listenerMutex.Lock()
+	defer r.listenerMutex.Unlock()
+	r.syncListeners = append(r.syncListeners, listener)
+}
+
+func (r *Client) notifySyncListeners(msgList []syncmsg.Message) {
+	if len(msgList) == 0 {
+		return
+	}
+	r.listenerMutex.RLock()
+	listeners := r.syncListeners
+	r.listenerMutex.RUnlock()
+	for _, listener := range listeners {
+		listener(msgList)
+	}
+}
@@ -0,0 +1,166 @@
+package syncmsg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 消息类型
+const (
+	MsgTypeText                = "text"                  // 文本消息
+	MsgTypeImage               = "image"                 // 图片消息
+	MsgTypeVoice               = "voice"                 // 语音消息
+	MsgTypeVideo               = "video"                 // 视频消息
+	MsgTypeFile                = "file"                  // 文件消息
+	MsgTypeLocation            = "location"              // 地理位置消息
+	MsgTypeLink                = "link"                  // 链接消息
+	MsgTypeBusinessCard        = "business_card"         // 名片消息
+	MsgTypeMiniProgram         = "miniprogram"           // 小程序消息
+	MsgTypeMsgMenu             = "msgmenu"               // 菜单消息
+	MsgTypeChannelsShopProduct = "channels_shop_product" // 视频号商品消息
+	MsgTypeChannelsShopOrder   = "channels_shop_order"   // 视频号订单消息
+	MsgTypeMergedMsg           = "merged_msg"            // 聊天记录消息
+	MsgTypeChannels            = "channels"              // 视频号消息
+	MsgTypeMeeting             = "meeting"               // 会议消息
+	MsgTypeSchedule            = "schedule"              // 日程消息
+	MsgTypeNote                = "note"                  // 笔记消息
+	MsgTypeEvent               = "event"                 // 事件消息
+)
+
+// Content 解析后的消息内容，可通过 type switch 获取具体的消息类型，如 *Text、*EnterSessionEvent
+type Content interface {
+	GetBaseMessage() BaseMessage
+	setBaseMessage(base BaseMessage)
+}
+
+// GetBaseMessage 获取消息公共字段
+func (r BaseMessage) GetBaseMessage() BaseMessage {
+	return r
+}
+
+func (r *BaseMessage) setBaseMessage(base BaseMessage) {
+	*r = base
+}
+
+// Unknown 暂不支持解析的消息类型，可通过 Message.OriginData 自行解析
+type Unknown struct {
+	BaseMessage
+	MsgType   string // 消息类型
+	EventType string // 事件类型
+}
+
+// messageHeader 消息公共字段，事件消息的客服帐号和客户取自事件内容
+type messageHeader struct {
+	BaseMessage
+	MsgType string `json:"msgtype"`
+	Event   struct {
+		EventType      string `json:"event_type"`
+		OpenKFID       string `json:"open_kfid"`
+		ExternalUserID string `json:"external_userid"`
+	} `json:"event"`
+}
+
+// NewMessage 解析sync_msg接口返回的单条原始消息，data 会直接作为 OriginData 保存，调用方不应再修改
+func NewMessage(data []byte) (Message, error) {
+	header := messageHeader{}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Message{}, err
+	}
+	msg := Message{
+		MsgID:              header.MsgID,
+		OpenKFID:           header.OpenKFID,
+		ExternalUserID:     header.ExternalUserID,
+		ReceptionistUserID: header.ReceptionistUserID,
+		SendTime:           header.SendTime,
+		Origin:             header.Origin,
+		MsgType:            header.MsgType,
+		OriginData:         data,
+	}
+	if msg.MsgType == MsgTypeEvent {
+		msg.EventType = header.Event.EventType
+		if msg.OpenKFID == "" {
+			msg.OpenKFID = header.Event.OpenKFID
+		}
+		if msg.ExternalUserID == "" {
+			msg.ExternalUserID = header.Event.ExternalUserID
+		}
+	}
+	return msg, nil
+}
+
+// Decode 按消息类型一次性解析为具体消息结构的指针，未知类型返回 *Unknown
+func (r Message) Decode() (Content, error) {
+	var content Content
+	switch r.MsgType {
+	case MsgTypeText:
+		content = &Text{}
+	case MsgTypeImage:
+		content = &Image{}
+	case MsgTypeVoice:
+		content = &Voice{}
+	case MsgTypeVideo:
+		content = &Video{}
+	case MsgTypeFile:
+		content = &File{}
+	case MsgTypeLocation:
+		content = &Location{}
+	case MsgTypeLink:
+		content = &Link{}
+	case MsgTypeBusinessCard:
+		content = &BusinessCard{}
+	case MsgTypeMiniProgram:
+		content = &MiniProgram{}
+	case MsgTypeMsgMenu:
+		content = &MsgMenu{}
+	case MsgTypeChannelsShopProduct:
+		content = &ChannelsShopProduct{}
+	case MsgTypeChannelsShopOrder:
+		content = &ChannelsShopOrder{}
+	case MsgTypeMergedMsg:
+		content = &MergedMsg{}
+	case MsgTypeChannels:
+		content = &Channels{}
+	case MsgTypeMeeting:
+		content = &Meeting{}
+	case MsgTypeSchedule:
+		content = &Schedule{}
+	case MsgTypeNote:
+		content = &Note{}
+	case MsgTypeEvent:
+		switch r.EventType {
+		case EventTypeEnterSession:
+			content = &EnterSessionEvent{}
+		case EventTypeMsgSendFail:
+			content = &MsgSendFailEvent{}
+		case EventTypeReceptionistStatusChange:
+			content = &ReceptionistStatusChangeEvent{}
+		case EventTypeSessionStatusChange:
+			content = &SessionStatusChangeEvent{}
+		case EventTypeUserRecallMsg:
+			content = &UserRecallMsgEvent{}
+		case EventTypeReceptionistRecallMsg:
+			content = &ReceptionistRecallMsgEvent{}
+		case EventTypeRejectCustomerMsgSwitchChange:
+			content = &RejectCustomerMsgSwitchChangeEvent{}
+		}
+	}
+	if content == nil {
+		return &Unknown{BaseMessage: r.baseMessage(), MsgType: r.MsgType, EventType: r.EventType}, nil
+	}
+	if err := json.Unmarshal(r.OriginData, content); err != nil {
+		return nil, fmt.Errorf("decode %s message %s: %w", r.MsgType, r.MsgID, err)
+	}
+	content.setBaseMessage(r.baseMessage())
+	return content, nil
+}
+
+func (r Message) baseMessage() BaseMessage {
+	return BaseMessage{
+		MsgID:              r.MsgID,
+		OpenKFID:           r.OpenKFID,
+		ExternalUserID:     r.ExternalUserID,
+		ReceptionistUserID: r.ReceptionistUserID,
+		SendTime:           r.SendTime,
+		Origin:             r.Origin,
+	}
+}
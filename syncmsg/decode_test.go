@@ -0,0 +1,319 @@
+package syncmsg
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		msgType        string
+		eventType      string
+		openKFID       string
+		externalUserID string
+	}{
+		{
+			name:           "text",
+			data:           `{"msgid":"1","open_kfid":"kf","external_userid":"user","send_time":1,"origin":3,"msgtype":"text","text":{"content":"hi"}}`,
+			msgType:        MsgTypeText,
+			openKFID:       "kf",
+			externalUserID: "user",
+		},
+		{
+			name:           "event fields are hoisted",
+			data:           `{"msgid":"2","send_time":1,"origin":4,"msgtype":"event","event":{"event_type":"enter_session","open_kfid":"kf","external_userid":"user","scene":"s","welcome_code":"code"}}`,
+			msgType:        MsgTypeEvent,
+			eventType:      EventTypeEnterSession,
+			openKFID:       "kf",
+			externalUserID: "user",
+		},
+		{
+			name:      "event without customer",
+			data:      `{"msgid":"3","send_time":1,"origin":4,"msgtype":"event","event":{"event_type":"servicer_status_change","open_kfid":"kf","servicer_userid":"servicer"}}`,
+			msgType:   MsgTypeEvent,
+			eventType: EventTypeReceptionistStatusChange,
+			openKFID:  "kf",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := NewMessage([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.MsgType != tt.msgType || msg.EventType != tt.eventType {
+				t.Fatalf("type = %s/%s, want %s/%s", msg.MsgType, msg.EventType, tt.msgType, tt.eventType)
+			}
+			if msg.OpenKFID != tt.openKFID || msg.ExternalUserID != tt.externalUserID {
+				t.Fatalf("open_kfid/external_userid = %s/%s, want %s/%s", msg.OpenKFID, msg.ExternalUserID, tt.openKFID, tt.externalUserID)
+			}
+			if string(msg.OriginData) != tt.data {
+				t.Fatalf("OriginData = %s, want original data", msg.OriginData)
+			}
+		})
+	}
+
+	if _, err := NewMessage([]byte(`{`)); err == nil {
+		t.Fatal("expected error for invalid json")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		msg, _ := NewMessage([]byte(`{"msgid":"1","open_kfid":"kf","external_userid":"user","msgtype":"text","text":{"content":"hi","menu_id":"m"}}`))
+		content, err := msg.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, ok := content.(*Text)
+		if !ok {
+			t.Fatalf("Decode() = %T, want *Text", content)
+		}
+		if text.Text.Content != "hi" || text.Text.MenuID != "m" || text.MsgID != "1" {
+			t.Fatalf("Decode() = %+v", text)
+		}
+	})
+
+	t.Run("event keeps hoisted base fields", func(t *testing.T) {
+		msg, _ := NewMessage([]byte(`{"msgid":"2","msgtype":"event","event":{"event_type":"enter_session","open_kfid":"kf","external_userid":"user","scene":"s","welcome_code":"code"}}`))
+		content, err := msg.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		event, ok := content.(*EnterSessionEvent)
+		if !ok {
+			t.Fatalf("Decode() = %T, want *EnterSessionEvent", content)
+		}
+		base := event.GetBaseMessage()
+		if base.OpenKFID != "kf" || base.ExternalUserID != "user" {
+			t.Fatalf("base = %+v, want hoisted open_kfid and external_userid", base)
+		}
+		if event.Event.Scene != "s" || event.Event.WelcomeCode != "code" {
+			t.Fatalf("event = %+v", event.Event)
+		}
+	})
+
+	t.Run("unknown types", func(t *testing.T) {
+		for _, data := range []string{
+			`{"msgid":"3","msgtype":"new_type"}`,
+			`{"msgid":"4","msgtype":"event","event":{"event_type":"new_event"}}`,
+		} {
+			msg, _ := NewMessage([]byte(data))
+			content, err := msg.Decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			unknown, ok := content.(*Unknown)
+			if !ok {
+				t.Fatalf("Decode() = %T, want *Unknown", content)
+			}
+			if unknown.MsgType != msg.MsgType || unknown.EventType != msg.EventType || unknown.MsgID != msg.MsgID {
+				t.Fatalf("Decode() = %+v", unknown)
+			}
+		}
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		msg := Message{MsgID: "5", MsgType: MsgTypeText, OriginData: []byte(`{"text":1}`)}
+		if _, err := msg.Decode(); err == nil {
+			t.Fatal("expected error for invalid content")
+		}
+	})
+}
+
+func TestDecodeNestedMergedMsg(t *testing.T) {
+	inner := mustJSON(t, map[string]interface{}{
+		"msgtype": "merged_msg",
+		"merged_msg": map[string]interface{}{
+			"title": "inner",
+			"item": []map[string]interface{}{
+				{"send_time": 2, "msgtype": "text", "sender_name": "b", "msg_content": mustJSON(t, map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": "deep"}})},
+			},
+		},
+	})
+	data := mustJSON(t, map[string]interface{}{
+		"msgid":   "1",
+		"msgtype": "merged_msg",
+		"merged_msg": map[string]interface{}{
+			"title": "outer",
+			"item": []map[string]interface{}{
+				{"send_time": 1, "msgtype": "merged_msg", "sender_name": "a", "msg_content": inner},
+			},
+		},
+	})
+
+	msg, err := NewMessage([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := msg.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer, ok := content.(*MergedMsg)
+	if !ok || len(outer.MergedMsg.Item) != 1 {
+		t.Fatalf("Decode() = %#v, want *MergedMsg with one item", content)
+	}
+
+	content, err = outer.MergedMsg.Item[0].Message().Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, ok := content.(*MergedMsg)
+	if !ok || nested.MergedMsg.Title != "inner" || len(nested.MergedMsg.Item) != 1 {
+		t.Fatalf("nested = %#v, want inner merged_msg", content)
+	}
+
+	content, err = nested.MergedMsg.Item[0].Message().Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := content.(*Text); !ok || text.Text.Content != "deep" || text.SendTime != 2 {
+		t.Fatalf("deepest = %#v, want text deep", content)
+	}
+}
+
+func mustJSON(tb testing.TB, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(data)
+}
+
+// newTestPage 生成1000条消息的原始分页数据，文本消息和事件交替出现
+func newTestPage(tb testing.TB) []json.RawMessage {
+	page := make([]json.RawMessage, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		if i%10 == 0 {
+			page = append(page, json.RawMessage(fmt.Sprintf(`{"msgid":"%d","send_time":1,"origin":4,"msgtype":"event","event":{"event_type":"enter_session","open_kfid":"kf","external_userid":"user","scene":"s"}}`, i)))
+			continue
+		}
+		page = append(page, json.RawMessage(fmt.Sprintf(`{"msgid":"%d","open_kfid":"kf","external_userid":"user","send_time":1,"origin":3,"msgtype":"text","text":{"content":"message %d"}}`, i, i)))
+	}
+	return page
+}
+
+func BenchmarkNewMessagePage(b *testing.B) {
+	page := newTestPage(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, raw := range page {
+			if _, err := NewMessage(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecodePage(b *testing.B) {
+	page := newTestPage(b)
+	msgList := make([]Message, 0, len(page))
+	for _, raw := range page {
+		msg, err := NewMessage(raw)
+		if err != nil {
+			b.Fatal(err)
+		}
+		msgList = append(msgList, msg)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, msg := range msgList {
+			if _, err := msg.Decode(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// newTestBody 生成包含1000条消息的拉取消息接口响应内容
+func newTestBody(tb testing.TB) []byte {
+	return []byte(mustJSON(tb, map[string]interface{}{
+		"errcode":     0,
+		"errmsg":      "ok",
+		"next_cursor": "c1",
+		"has_more":    0,
+		"msg_list":    newTestPage(tb),
+	}))
+}
+
+// legacyMessage 旧版本基于map的解析方式：整页解析为map后逐条读取公共字段，再重新序列化得到原始内容
+func legacyMessage(msg map[string]interface{}) (Message, error) {
+	newMsg := Message{}
+	if val, ok := msg["msgid"].(string); ok {
+		newMsg.MsgID = val
+	}
+	if val, ok := msg["open_kfid"].(string); ok {
+		newMsg.OpenKFID = val
+	}
+	if val, ok := msg["external_userid"].(string); ok {
+		newMsg.ExternalUserID = val
+	}
+	if val, ok := msg["send_time"].(float64); ok {
+		newMsg.SendTime = uint64(val)
+	}
+	if val, ok := msg["origin"].(float64); ok {
+		newMsg.Origin = uint32(val)
+	}
+	if val, ok := msg["msgtype"].(string); ok {
+		newMsg.MsgType = val
+	}
+	if newMsg.MsgType == "event" {
+		if event, ok := msg["event"].(map[string]interface{}); ok {
+			if eType, ok := event["event_type"].(string); ok {
+				newMsg.EventType = eType
+			}
+		}
+	}
+	originData, err := json.Marshal(msg)
+	if err != nil {
+		return newMsg, err
+	}
+	newMsg.OriginData = originData
+	return newMsg, nil
+}
+
+// BenchmarkSyncPageMap 旧版本解析1000条消息分页的基线
+func BenchmarkSyncPageMap(b *testing.B) {
+	body := newTestBody(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		info := struct {
+			MsgList []map[string]interface{} `json:"msg_list"`
+		}{}
+		if err := json.Unmarshal(body, &info); err != nil {
+			b.Fatal(err)
+		}
+		for _, msg := range info.MsgList {
+			if _, err := legacyMessage(msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkSyncPageRaw 当前解析1000条消息分页的方式，与 BenchmarkSyncPageMap 对比
+func BenchmarkSyncPageRaw(b *testing.B) {
+	body := newTestBody(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		info := struct {
+			MsgList []json.RawMessage `json:"msg_list"`
+		}{}
+		if err := json.Unmarshal(body, &info); err != nil {
+			b.Fatal(err)
+		}
+		for _, raw := range info.MsgList {
+			if _, err := NewMessage(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
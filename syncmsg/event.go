@@ -0,0 +1,12 @@
+package syncmsg
+
+// 事件类型
+const (
+	EventTypeEnterSession                  = "enter_session"                     // 用户进入会话事件
+	EventTypeMsgSendFail                   = "msg_send_fail"                     // 消息发送失败事件
+	EventTypeReceptionistStatusChange      = "servicer_status_change"            // 接待人员接待状态变更事件
+	EventTypeSessionStatusChange           = "session_status_change"             // 会话状态变更事件
+	EventTypeUserRecallMsg                 = "user_recall_msg"                   // 用户撤回消息事件
+	EventTypeReceptionistRecallMsg         = "servicer_recall_msg"               // 接待人员撤回消息事件
+	EventTypeRejectCustomerMsgSwitchChange = "reject_customer_msg_switch_change" // 拒收客户消息变更事件
+)
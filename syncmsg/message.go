@@ -1,5 +1,7 @@
 package syncmsg
 
+import "github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsg"
+
 // BaseMessage 接收消息
 type BaseMessage struct {
 	MsgID              string `json:"msgid"`           // 消息ID
@@ -101,6 +103,108 @@ type MiniProgram struct {
 	} `json:"miniprogram"` // 小程序消息
 }
 
+// MsgMenu 菜单消息
+type MsgMenu struct {
+	BaseMessage
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：msgmenu
+	MsgMenu struct {
+		HeadContent string           `json:"head_content"` // 起始文本
+		List        sendmsg.MenuList `json:"list"`         // 菜单项配置，与发送菜单消息使用相同的菜单项类型
+		TailContent string           `json:"tail_content"` // 结束文本
+	} `json:"msgmenu"` // 菜单消息
+}
+
+// ChannelsShopProduct 视频号商品消息
+type ChannelsShopProduct struct {
+	BaseMessage
+	MsgType             string `json:"msgtype"` // 消息类型，此时固定为：channels_shop_product
+	ChannelsShopProduct struct {
+		ProductID     string `json:"product_id"`      // 商品ID
+		HeadImage     string `json:"head_image"`      // 商品图片
+		Title         string `json:"title"`           // 商品标题
+		SalesPrice    string `json:"sales_price"`     // 商品价格，以分为单位
+		ShopNickname  string `json:"shop_nickname"`   // 店铺名称
+		ShopHeadImage string `json:"shop_head_image"` // 店铺头像
+	} `json:"channels_shop_product"` // 视频号商品消息
+}
+
+// ChannelsShopOrder 视频号订单消息
+type ChannelsShopOrder struct {
+	BaseMessage
+	MsgType           string `json:"msgtype"` // 消息类型，此时固定为：channels_shop_order
+	ChannelsShopOrder struct {
+		OrderID       string `json:"order_id"`       // 订单号
+		ProductTitles string `json:"product_titles"` // 商品标题
+		PriceWording  string `json:"price_wording"`  // 订单价格描述
+		State         string `json:"state"`          // 订单状态
+		ImageURL      string `json:"image_url"`      // 订单缩略图
+		ShopNickname  string `json:"shop_nickname"`  // 店铺名称
+	} `json:"channels_shop_order"` // 视频号订单消息
+}
+
+// MergedMsg 聊天记录消息
+type MergedMsg struct {
+	BaseMessage
+	MsgType   string `json:"msgtype"` // 消息类型，此时固定为：merged_msg
+	MergedMsg struct {
+		Title string          `json:"title"` // 聊天记录标题
+		Item  []MergedMsgItem `json:"item"`  // 消息记录内的消息内容，可能嵌套聊天记录
+	} `json:"merged_msg"` // 聊天记录消息
+}
+
+// MergedMsgItem 聊天记录中的消息
+type MergedMsgItem struct {
+	SendTime   uint64 `json:"send_time"`   // 消息发送时间
+	MsgType    string `json:"msgtype"`     // 消息类型
+	SenderName string `json:"sender_name"` // 发送者名称
+	MsgContent string `json:"msg_content"` // 消息内容，JSON字符串，格式与对应类型的消息一致
+}
+
+// Message 将聊天记录中的消息转换为 Message，可继续通过 GetXxx 方法解析，嵌套的聊天记录可递归解析
+func (r MergedMsgItem) Message() Message {
+	return Message{
+		SendTime:   r.SendTime,
+		MsgType:    r.MsgType,
+		OriginData: []byte(r.MsgContent),
+	}
+}
+
+// Channels 视频号消息
+type Channels struct {
+	BaseMessage
+	MsgType  string `json:"msgtype"` // 消息类型，此时固定为：channels
+	Channels struct {
+		SubType  uint32 `json:"sub_type"` // 视频号消息类型。1-视频号动态 2-视频号直播 3-视频号名片
+		Nickname string `json:"nickname"` // 视频号名称
+		Title    string `json:"title"`    // 视频号动态标题，视频号消息类型为1和2时返回
+	} `json:"channels"` // 视频号消息
+}
+
+// Meeting 会议消息
+type Meeting struct {
+	BaseMessage
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：meeting
+	Meeting struct {
+		MeetingID string `json:"meetingid"` // 会议ID
+	} `json:"meeting"` // 会议消息
+}
+
+// Schedule 日程消息
+type Schedule struct {
+	BaseMessage
+	MsgType  string `json:"msgtype"` // 消息类型，此时固定为：schedule
+	Schedule struct {
+		ScheduleID string `json:"schedule_id"` // 日程ID
+	} `json:"schedule"` // 日程消息
+}
+
+// Note 笔记消息，笔记内容不会返回
+type Note struct {
+	BaseMessage
+	MsgType string   `json:"msgtype"` // 消息类型，此时固定为：note
+	Note    struct{} `json:"note"`    // 笔记消息
+}
+
 // EventMessage 事件消息
 type EventMessage struct {
 	BaseMessage
@@ -145,6 +249,7 @@ type ReceptionistStatusChangeEvent struct {
 		EventType          string `json:"event_type"`      // 事件类型。此处固定为：servicer_status_change
 		ReceptionistUserID string `json:"servicer_userid"` // 客服人员userid
 		Status             uint32 `json:"status"`          // 状态类型。1-接待中 2-停止接待
+		StopType           uint32 `json:"stop_type"`       // 停止接待的子类型。0:停止接待 1:暂时挂起，仅status为2时有效
 		OpenKFID           string `json:"open_kfid"`       // 客服帐号ID
 	} `json:"event"`
 }
@@ -163,3 +268,41 @@ type SessionStatusChangeEvent struct {
 		MsgCode               string `json:"msg_code"`            // 用于发送事件响应消息的code，仅change_type为1和3时，会返回该字段。可用该msg_code调用发送事件响应消息接口给客户发送回复语或结束语。
 	} `json:"event"` // 事件消息
 }
+
+// UserRecallMsgEvent 用户撤回消息事件
+type UserRecallMsgEvent struct {
+	BaseMessage
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：event
+	Event   struct {
+		EventType      string `json:"event_type"`      // 事件类型。此处固定为：user_recall_msg
+		OpenKFID       string `json:"open_kfid"`       // 客服账号ID
+		ExternalUserID string `json:"external_userid"` // 客户UserID
+		RecallMsgID    string `json:"recall_msgid"`    // 撤回的消息msgid
+	} `json:"event"` // 事件消息
+}
+
+// ReceptionistRecallMsgEvent 接待人员撤回消息事件
+type ReceptionistRecallMsgEvent struct {
+	BaseMessage
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：event
+	Event   struct {
+		EventType          string `json:"event_type"`      // 事件类型。此处固定为：servicer_recall_msg
+		OpenKFID           string `json:"open_kfid"`       // 客服账号ID
+		ExternalUserID     string `json:"external_userid"` // 客户UserID
+		RecallMsgID        string `json:"recall_msgid"`    // 撤回的消息msgid
+		ReceptionistUserID string `json:"servicer_userid"` // 撤回消息的接待人员userid
+	} `json:"event"` // 事件消息
+}
+
+// RejectCustomerMsgSwitchChangeEvent 拒收客户消息变更事件
+type RejectCustomerMsgSwitchChangeEvent struct {
+	BaseMessage
+	MsgType string `json:"msgtype"` // 消息类型，此时固定为：event
+	Event   struct {
+		EventType          string `json:"event_type"`      // 事件类型。此处固定为：reject_customer_msg_switch_change
+		ReceptionistUserID string `json:"servicer_userid"` // 操作的接待人员userid
+		OpenKFID           string `json:"open_kfid"`       // 客服账号ID
+		ExternalUserID     string `json:"external_userid"` // 客户UserID
+		RejectSwitch       uint32 `json:"reject_switch"`   // 拒收客户消息，1表示接待人员拒收了客户消息，0表示接待人员取消拒收客户消息
+	} `json:"event"` // 事件消息
+}
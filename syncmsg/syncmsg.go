@@ -5,8 +5,8 @@ import "encoding/json"
 // Message 同步的消息内容
 type Message struct {
 	MsgID              string `json:"msgid"`           // 消息ID
-	OpenKFID           string `json:"open_kfid"`       // 客服帐号ID（msgtype为event，该字段不返回）
-	ExternalUserID     string `json:"external_userid"` // 客户UserID（msgtype为event，该字段不返回）
+	OpenKFID           string `json:"open_kfid"`       // 客服帐号ID（msgtype为event时取自事件内容）
+	ExternalUserID     string `json:"external_userid"` // 客户UserID（msgtype为event时取自事件内容）
 	ReceptionistUserID string `json:"servicer_userid"` // 接待客服userID
 	SendTime           uint64 `json:"send_time"`       // 消息发送时间
 	Origin             uint32 `json:"origin"`          // 消息来源。3-微信客户发送的消息 4-系统推送的事件消息 5-接待人员在企业微信客户端发送的消息
@@ -74,6 +74,54 @@ func (r Message) GetMiniProgramMessage() (info MiniProgram, err error) {
 	return info, err
 }
 
+// GetMsgMenuMessage 获取菜单消息
+func (r Message) GetMsgMenuMessage() (info MsgMenu, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetChannelsShopProductMessage 获取视频号商品消息
+func (r Message) GetChannelsShopProductMessage() (info ChannelsShopProduct, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetChannelsShopOrderMessage 获取视频号订单消息
+func (r Message) GetChannelsShopOrderMessage() (info ChannelsShopOrder, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetMergedMsgMessage 获取聊天记录消息，可通过 MergedMsgItem.Message 继续解析其中的消息
+func (r Message) GetMergedMsgMessage() (info MergedMsg, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetChannelsMessage 获取视频号消息
+func (r Message) GetChannelsMessage() (info Channels, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetMeetingMessage 获取会议消息
+func (r Message) GetMeetingMessage() (info Meeting, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetScheduleMessage 获取日程消息
+func (r Message) GetScheduleMessage() (info Schedule, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
+// GetNoteMessage 获取笔记消息
+func (r Message) GetNoteMessage() (info Note, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	return info, err
+}
+
 // GetEnterSessionEvent 用户进入会话事件
 func (r Message) GetEnterSessionEvent() (info EnterSessionEvent, err error) {
 	err = json.Unmarshal(r.OriginData, &info)
@@ -101,3 +149,27 @@ func (r Message) GetSessionStatusChangeEvent() (info SessionStatusChangeEvent, e
 	info.ExternalUserID = info.Event.ExternalUserID
 	return info, err
 }
+
+// GetUserRecallMsgEvent 用户撤回消息事件
+func (r Message) GetUserRecallMsgEvent() (info UserRecallMsgEvent, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	info.OpenKFID = info.Event.OpenKFID
+	info.ExternalUserID = info.Event.ExternalUserID
+	return info, err
+}
+
+// GetReceptionistRecallMsgEvent 接待人员撤回消息事件
+func (r Message) GetReceptionistRecallMsgEvent() (info ReceptionistRecallMsgEvent, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	info.OpenKFID = info.Event.OpenKFID
+	info.ExternalUserID = info.Event.ExternalUserID
+	return info, err
+}
+
+// GetRejectCustomerMsgSwitchChangeEvent 拒收客户消息变更事件
+func (r Message) GetRejectCustomerMsgSwitchChangeEvent() (info RejectCustomerMsgSwitchChangeEvent, err error) {
+	err = json.Unmarshal(r.OriginData, &info)
+	info.OpenKFID = info.Event.OpenKFID
+	info.ExternalUserID = info.Event.ExternalUserID
+	return info, err
+}
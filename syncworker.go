@@ -0,0 +1,136 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// SyncWorkerOptions 按客服帐号并行拉取消息的参数
+type SyncWorkerOptions struct {
+	Limit        uint                                                   // 每次拉取的消息数量，默认值和最大值都为1000
+	VoiceFormat  uint32                                                 // 语音消息类型，0-Amr 1-Silk，默认0
+	Handler      func(openKFID string, msgList []syncmsg.Message) error // 消息处理函数，必填，返回错误时本页游标不会保存
+	ErrorHandler func(openKFID string, err error)                       // 拉取或处理消息失败时的回调，可为空
+}
+
+// SyncWorkerPool 每个客服帐号独立维护游标和拉取协程，避免单个帐号的消息积压影响其他帐号
+type SyncWorkerPool struct {
+	client  *Client
+	options SyncWorkerOptions
+	ctx     context.Context
+	mutex   sync.Mutex
+	workers map[string]*syncWorker
+	wg      sync.WaitGroup
+}
+
+// syncWorker 单个客服帐号的拉取协程
+type syncWorker struct {
+	openKFID string
+	tokens   chan string // 待处理的回调token，只保留最新的一个
+}
+
+// NewSyncWorkerPool 初始化按客服帐号并行拉取消息的协程池
+func (r *Client) NewSyncWorkerPool(options SyncWorkerOptions) *SyncWorkerPool {
+	return &SyncWorkerPool{
+		client:  r,
+		options: options,
+		workers: make(map[string]*syncWorker),
+	}
+}
+
+// Start 通过获取客服账号列表为每个客服帐号启动拉取协程，ctx 取消后所有协程退出
+// 未指定 Handler 时返回 SDKSyncHandlerMissing，不会启动任何协程
+func (r *SyncWorkerPool) Start(ctx context.Context) error {
+	if r.options.Handler == nil {
+		return NewSDKErr(50011)
+	}
+	r.mutex.Lock()
+	r.ctx = ctx
+	r.mutex.Unlock()
+
+	info, err := r.client.AccountList()
+	if err != nil {
+		return err
+	}
+	for _, account := range info.AccountList {
+		r.worker(account.OpenKFID)
+	}
+	return nil
+}
+
+// Notify 收到回调事件后触发对应客服帐号拉取消息
+func (r *SyncWorkerPool) Notify(event syncmsg.Event) {
+	r.Trigger(event.OpenKFID, event.Token)
+}
+
+// Trigger 触发指定客服帐号拉取消息，未知的客服帐号会自动启动新的拉取协程
+// 拉取协程繁忙时多次触发会合并为一次，并使用最新的token
+func (r *SyncWorkerPool) Trigger(openKFID, token string) {
+	worker := r.worker(openKFID)
+	if worker == nil {
+		return
+	}
+	for {
+		select {
+		case worker.tokens <- token:
+			return
+		default:
+		}
+		//丢弃尚未处理的旧token
+		select {
+		case <-worker.tokens:
+		default:
+		}
+	}
+}
+
+// Wait 等待所有拉取协程退出
+func (r *SyncWorkerPool) Wait() {
+	r.wg.Wait()
+}
+
+// worker 获取或创建客服帐号对应的拉取协程，协程池未启动时返回nil
+func (r *SyncWorkerPool) worker(openKFID string) *syncWorker {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ctx == nil || r.ctx.Err() != nil {
+		return nil
+	}
+	if worker, ok := r.workers[openKFID]; ok {
+		return worker
+	}
+	worker := &syncWorker{
+		openKFID: openKFID,
+		tokens:   make(chan string, 1),
+	}
+	r.workers[openKFID] = worker
+
+	r.wg.Add(1)
+	go r.run(r.ctx, worker)
+	return worker
+}
+
+func (r *SyncWorkerPool) run(ctx context.Context, worker *syncWorker) {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case token := <-worker.tokens:
+			err := r.client.SyncAll(SyncMsgOptions{
+				Token:       token,
+				Limit:       r.options.Limit,
+				OpenKFID:    worker.openKFID,
+				VoiceFormat: r.options.VoiceFormat,
+			}, func(msgList []syncmsg.Message) error {
+				return r.options.Handler(worker.openKFID, msgList)
+			})
+			if err != nil && r.options.ErrorHandler != nil {
+				r.options.ErrorHandler(worker.openKFID, err)
+			}
+		}
+	}
+}
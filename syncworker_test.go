@@ -0,0 +1,193 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// workerServer 模拟客服账号列表和拉取消息接口，记录每个客服帐号拉取时携带的token
+type workerServer struct {
+	mutex  sync.Mutex
+	tokens map[string][]string
+}
+
+func newWorkerServer(t *testing.T, accounts ...string) *workerServer {
+	server := &workerServer{tokens: make(map[string][]string)}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/account/list") {
+			list := make([]map[string]string, 0, len(accounts))
+			for _, account := range accounts {
+				list = append(list, map[string]string{"open_kfid": account})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "account_list": list})
+			return
+		}
+		var options SyncMsgOptions
+		_ = json.NewDecoder(req.Body).Decode(&options)
+		server.mutex.Lock()
+		server.tokens[options.OpenKFID] = append(server.tokens[options.OpenKFID], options.Token)
+		server.mutex.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"errcode": 0,
+			"msg_list": []map[string]interface{}{{
+				"msgid":           options.Token,
+				"open_kfid":       options.OpenKFID,
+				"external_userid": "user",
+				"origin":          3,
+				"msgtype":         "text",
+				"text":            map[string]string{"content": options.Token},
+			}},
+		})
+	})
+	return server
+}
+
+func (r *workerServer) requestedTokens(openKFID string) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.tokens[openKFID]...)
+}
+
+// handled 记录每个客服帐号处理的消息，格式为 消息所属客服帐号/msgid
+type handled struct {
+	mutex  sync.Mutex
+	msgIDs map[string][]string
+	done   chan struct{}
+}
+
+func (r *handled) handler(openKFID string, msgList []syncmsg.Message) error {
+	r.mutex.Lock()
+	for _, msg := range msgList {
+		r.msgIDs[openKFID] = append(r.msgIDs[openKFID], msg.OpenKFID+"/"+msg.MsgID)
+	}
+	r.mutex.Unlock()
+	r.done <- struct{}{}
+	return nil
+}
+
+func waitHandled(t *testing.T, done chan struct{}, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("handled %d of %d pages before timeout", i, count)
+		}
+	}
+}
+
+func TestSyncWorkerPoolRequiresHandler(t *testing.T) {
+	server := newWorkerServer(t, "kf1")
+	client := newTestClient(t, Options{CorpID: "corp"})
+	pool := client.NewSyncWorkerPool(SyncWorkerOptions{})
+
+	if err := pool.Start(context.Background()); err != SDKSyncHandlerMissing {
+		t.Fatalf("Start() err = %v, want %v", err, SDKSyncHandlerMissing)
+	}
+	pool.Trigger("kf1", "token")
+	pool.Wait()
+	if tokens := server.requestedTokens("kf1"); len(tokens) != 0 {
+		t.Fatalf("requested tokens = %v, want none", tokens)
+	}
+}
+
+func TestSyncWorkerPoolDispatchesPerAccount(t *testing.T) {
+	server := newWorkerServer(t, "kf1", "kf2")
+	client := newTestClient(t, Options{CorpID: "corp"})
+	result := &handled{msgIDs: make(map[string][]string), done: make(chan struct{}, 10)}
+	pool := client.NewSyncWorkerPool(SyncWorkerOptions{Handler: result.handler})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pool.Trigger("kf1", "t1")
+	pool.Notify(syncmsg.Event{OpenKFID: "kf2", Token: "t2"})
+	pool.Trigger("kf3", "t3") //未在账号列表中的客服帐号自动启动协程
+	waitHandled(t, result.done, 3)
+
+	for openKFID, want := range map[string]string{"kf1": "t1", "kf2": "t2", "kf3": "t3"} {
+		if tokens := server.requestedTokens(openKFID); !equalStrings(tokens, []string{want}) {
+			t.Fatalf("%s requested tokens = %v, want [%s]", openKFID, tokens, want)
+		}
+		if got := result.msgIDs[openKFID]; !equalStrings(got, []string{openKFID + "/" + want}) {
+			t.Fatalf("%s handled %v, want [%s/%s]", openKFID, got, openKFID, want)
+		}
+	}
+}
+
+func TestSyncWorkerPoolCoalescesTriggers(t *testing.T) {
+	server := newWorkerServer(t)
+	client := newTestClient(t, Options{CorpID: "corp"})
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{}, 10)
+	first := true
+	pool := client.NewSyncWorkerPool(SyncWorkerOptions{Handler: func(openKFID string, msgList []syncmsg.Message) error {
+		if first {
+			first = false
+			close(entered)
+			<-release
+		}
+		done <- struct{}{}
+		return nil
+	}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pool.Trigger("kf", "t1")
+	<-entered
+	//协程繁忙时多次触发合并为一次，使用最新的token
+	for _, token := range []string{"t2", "t3", "t4"} {
+		pool.Trigger("kf", token)
+	}
+	close(release)
+	waitHandled(t, done, 2)
+
+	if tokens := server.requestedTokens("kf"); !equalStrings(tokens, []string{"t1", "t4"}) {
+		t.Fatalf("requested tokens = %v, want [t1 t4]", tokens)
+	}
+}
+
+func TestSyncWorkerPoolShutdown(t *testing.T) {
+	server := newWorkerServer(t, "kf1", "kf2")
+	client := newTestClient(t, Options{CorpID: "corp"})
+	pool := client.NewSyncWorkerPool(SyncWorkerOptions{Handler: func(string, []syncmsg.Message) error { return nil }})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := pool.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	waited := make(chan struct{})
+	go func() {
+		pool.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("workers did not exit after ctx was canceled")
+	}
+
+	//关闭后的触发不会启动新的拉取
+	pool.Trigger("kf1", "late")
+	pool.Trigger("kf3", "late")
+	pool.Wait()
+	if tokens := server.requestedTokens("kf1"); len(tokens) != 0 {
+		t.Fatalf("requested tokens = %v, want none after shutdown", tokens)
+	}
+	if tokens := server.requestedTokens("kf3"); len(tokens) != 0 {
+		t.Fatalf("requested tokens = %v, want none after shutdown", tokens)
+	}
+}
@@ -3,10 +3,12 @@ package util
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -19,14 +21,58 @@ func HttpGet(path string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, err
+		return nil, fmt.Errorf("unexpected http status: %s", resp.Status)
 	}
 	return ioutil.ReadAll(resp.Body)
 }
 
+// HttpGetStream GET请求，返回未读取的响应供调用方流式处理，调用方需关闭响应内容
+// 支持设置请求头，状态码为200或206时视为成功
+func HttpGetStream(path string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected http status: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// redactURLError 隐藏请求错误中链接携带的access_token，避免泄露到日志中
+func redactURLError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	link, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return &url.Error{Op: urlErr.Op, URL: "", Err: urlErr.Err}
+	}
+	query := link.Query()
+	if query.Get("access_token") != "" {
+		query.Set("access_token", "REDACTED")
+		link.RawQuery = query.Encode()
+	}
+	return &url.Error{Op: urlErr.Op, URL: link.String(), Err: urlErr.Err}
+}
+
 // HttpPost POST请求
 func HttpPost(path string, body interface{}) ([]byte, error) {
-	params, _ := json.Marshal(body)
+	params, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := http.Post(path, "application/json;charset=utf-8", bytes.NewBuffer(params))
 	if err != nil {
 		return nil, err
@@ -34,7 +80,7 @@ func HttpPost(path string, body interface{}) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, err
+		return nil, fmt.Errorf("unexpected http status: %s", resp.Status)
 	}
 	return ioutil.ReadAll(resp.Body)
 }
@@ -76,7 +122,7 @@ func HttpPostFile(path string, options FileOptions) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, err
+		return nil, fmt.Errorf("unexpected http status: %s", resp.Status)
 	}
 	return ioutil.ReadAll(resp.Body)
 }
@@ -108,7 +154,7 @@ func HttpPostOriginFile(path, fileName string, size int, body []byte) ([]byte, e
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, err
+		return nil, fmt.Errorf("unexpected http status: %s", resp.Status)
 	}
 	return ioutil.ReadAll(resp.Body)
 }
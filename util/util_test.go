@@ -0,0 +1,56 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHttpGetStreamRedactsAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	link := server.URL + "/cgi-bin/media/get?access_token=secret-token&media_id=1"
+	server.Close()
+
+	_, err := HttpGetStream(link, nil)
+	if err == nil {
+		t.Fatal("expected transport error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("error leaks access_token: %v", err)
+	}
+	if !strings.Contains(err.Error(), "media_id=1") {
+		t.Fatalf("error lost request context: %v", err)
+	}
+}
+
+func TestHttpGetStreamStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "partial content", status: http.StatusPartialContent},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Range") != "bytes=0-9" {
+					t.Errorf("Range = %q, want bytes=0-9", req.Header.Get("Range"))
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			resp, err := HttpGetStream(server.URL, http.Header{"Range": []string{"bytes=0-9"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
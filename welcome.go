@@ -0,0 +1,218 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/sendmsgonevent"
+	"github.com/NICEXAI/WeChatCustomerServiceSDK/syncmsg"
+)
+
+// welcome_code 的有效期
+const welcomeCodeExpireTime = 20 * time.Second
+
+// 欢迎语发送状态
+const (
+	WelcomeStatusSent    = "sent"    // 发送成功
+	WelcomeStatusExpired = "expired" // welcome_code 已过期，未发送
+	WelcomeStatusFailed  = "failed"  // 发送失败
+)
+
+// WelcomeBuilder 根据welcome_code和进入会话事件生成事件响应消息，仅支持文本和菜单消息
+type WelcomeBuilder func(code string, event syncmsg.EnterSessionEvent) (interface{}, error)
+
+// WelcomeText 固定内容的文本欢迎语
+func WelcomeText(content string) WelcomeBuilder {
+	return func(code string, event syncmsg.EnterSessionEvent) (interface{}, error) {
+		return sendmsgonevent.NewText(code, content), nil
+	}
+}
+
+// WelcomeMenu 固定内容的菜单欢迎语
+func WelcomeMenu(headContent string, list sendmsgonevent.MenuList, tailContent string) WelcomeBuilder {
+	return func(code string, event syncmsg.EnterSessionEvent) (interface{}, error) {
+		return sendmsgonevent.NewMenu(code, headContent, list, tailContent), nil
+	}
+}
+
+// WelcomeRule 欢迎语规则，条件为空时匹配任意值
+type WelcomeRule struct {
+	OpenKFID   string         // 客服帐号ID
+	Scene      string         // 进入会话的场景值
+	SceneParam string         // 进入会话的自定义参数
+	Builder    WelcomeBuilder // 欢迎语
+}
+
+func (r WelcomeRule) match(event syncmsg.EnterSessionEvent) bool {
+	if r.OpenKFID != "" && r.OpenKFID != event.Event.OpenKFID {
+		return false
+	}
+	if r.Scene != "" && r.Scene != event.Event.Scene {
+		return false
+	}
+	if r.SceneParam != "" && r.SceneParam != event.Event.SceneParam {
+		return false
+	}
+	return true
+}
+
+// WelcomeResult 欢迎语发送结果
+type WelcomeResult struct {
+	OpenKFID       string    // 客服帐号ID
+	ExternalUserID string    // 客户UserID
+	Scene          string    // 进入会话的场景值
+	SceneParam     string    // 进入会话的自定义参数
+	MsgID          string    // 发送成功时的消息ID
+	Status         string    // 发送状态
+	Err            error     // 发送失败的原因
+	Deadline       time.Time // welcome_code 的过期时间
+}
+
+// WelcomeStats 欢迎语发送统计
+type WelcomeStats struct {
+	Sent    uint64 // 发送成功次数
+	Expired uint64 // welcome_code 过期次数
+	Failed  uint64 // 发送失败次数
+}
+
+// WelcomeResponderOptions 欢迎语自动回复参数
+type WelcomeResponderOptions struct {
+	Rules    []WelcomeRule       // 欢迎语规则，按顺序匹配第一条满足条件的规则
+	OnResult func(WelcomeResult) // 发送完成后的回调，可选
+}
+
+// WelcomeResponder 欢迎语自动回复，拉取到带welcome_code的进入会话事件后，在code过期前发送匹配的欢迎语
+type WelcomeResponder struct {
+	client  *Client
+	options WelcomeResponderOptions
+	sent    uint64
+	expired uint64
+	failed  uint64
+	wg      sync.WaitGroup
+	mutex   sync.Mutex
+	codes   map[string]time.Time // 已处理的welcome_code及其过期时间，避免重复投递的事件再次发送
+}
+
+// NewWelcomeResponder 初始化欢迎语自动回复，之后拉取到的进入会话事件会自动发送欢迎语
+func (r *Client) NewWelcomeResponder(options WelcomeResponderOptions) *WelcomeResponder {
+	responder := &WelcomeResponder{
+		client:  r,
+		options: options,
+		codes:   make(map[string]time.Time),
+	}
+	r.addSyncListener(responder.handleSynced)
+	return responder
+}
+
+// Stats 获取欢迎语发送统计
+func (r *WelcomeResponder) Stats() WelcomeStats {
+	return WelcomeStats{
+		Sent:    atomic.LoadUint64(&r.sent),
+		Expired: atomic.LoadUint64(&r.expired),
+		Failed:  atomic.LoadUint64(&r.failed),
+	}
+}
+
+// Wait 等待正在发送的欢迎语完成
+func (r *WelcomeResponder) Wait() {
+	r.wg.Wait()
+}
+
+// handleSynced 异步发送欢迎语，避免阻塞消息拉取
+func (r *WelcomeResponder) handleSynced(msgList []syncmsg.Message) {
+	for _, msg := range msgList {
+		if msg.EventType != syncmsg.EventTypeEnterSession {
+			continue
+		}
+		event, err := msg.GetEnterSessionEvent()
+		if err != nil || event.Event.WelcomeCode == "" {
+			continue
+		}
+		rule, ok := r.findRule(event)
+		if !ok {
+			continue
+		}
+		deadline := time.Unix(int64(msg.SendTime), 0).Add(welcomeCodeExpireTime)
+		if !r.claimCode(event.Event.WelcomeCode, deadline) {
+			continue
+		}
+		r.wg.Add(1)
+		go func(event syncmsg.EnterSessionEvent) {
+			defer r.wg.Done()
+			r.respond(rule, event, deadline)
+		}(event)
+	}
+}
+
+// claimCode 记录welcome_code，已处理过时返回false，同时清理已过期的记录
+func (r *WelcomeResponder) claimCode(code string, deadline time.Time) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	for item, expireAt := range r.codes {
+		if now.After(expireAt) {
+			delete(r.codes, item)
+		}
+	}
+	if _, ok := r.codes[code]; ok {
+		return false
+	}
+	r.codes[code] = deadline
+	return true
+}
+
+func (r *WelcomeResponder) findRule(event syncmsg.EnterSessionEvent) (WelcomeRule, bool) {
+	for _, rule := range r.options.Rules {
+		if rule.Builder != nil && rule.match(event) {
+			return rule, true
+		}
+	}
+	return WelcomeRule{}, false
+}
+
+func (r *WelcomeResponder) respond(rule WelcomeRule, event syncmsg.EnterSessionEvent, deadline time.Time) {
+	result := WelcomeResult{
+		OpenKFID:       event.Event.OpenKFID,
+		ExternalUserID: event.Event.ExternalUserID,
+		Scene:          event.Event.Scene,
+		SceneParam:     event.Event.SceneParam,
+		Deadline:       deadline,
+	}
+	defer r.report(&result)
+
+	if !time.Now().Before(deadline) {
+		result.Status = WelcomeStatusExpired
+		return
+	}
+	message, err := rule.Builder(event.Event.WelcomeCode, event)
+	if err != nil {
+		result.Status, result.Err = WelcomeStatusFailed, err
+		return
+	}
+	info, err := r.client.SendMsgOnEvent(message)
+	if err != nil {
+		//发送过程中code已过期时记为过期
+		if !time.Now().Before(deadline) {
+			result.Status, result.Err = WelcomeStatusExpired, err
+			return
+		}
+		result.Status, result.Err = WelcomeStatusFailed, err
+		return
+	}
+	result.Status, result.MsgID = WelcomeStatusSent, info.MsgID
+}
+
+func (r *WelcomeResponder) report(result *WelcomeResult) {
+	switch result.Status {
+	case WelcomeStatusSent:
+		atomic.AddUint64(&r.sent, 1)
+	case WelcomeStatusExpired:
+		atomic.AddUint64(&r.expired, 1)
+	default:
+		atomic.AddUint64(&r.failed, 1)
+	}
+	if r.options.OnResult != nil {
+		r.options.OnResult(*result)
+	}
+}
@@ -0,0 +1,196 @@
+package WeChatCustomerServiceSDK
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// welcomeServer 模拟拉取消息和发送事件响应消息接口
+type welcomeServer struct {
+	mutex    sync.Mutex
+	msgList  []map[string]interface{}
+	failCode string            // 发送时返回错误的welcome_code
+	sent     map[string]string // welcome_code -> 文本内容
+}
+
+func newWelcomeServer(t *testing.T) *welcomeServer {
+	server := &welcomeServer{sent: make(map[string]string)}
+	newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		if strings.HasSuffix(req.URL.Path, "/sync_msg") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "next_cursor": "c1", "msg_list": server.msgList})
+			return
+		}
+		body := struct {
+			Code  string `json:"code"`
+			MsgID string `json:"msgid"`
+			Text  struct {
+				Content string `json:"content"`
+			} `json:"text"`
+		}{}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		if body.Code == server.failCode {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 95018, "errmsg": "invalid code"})
+			return
+		}
+		server.sent[body.Code] = body.Text.Content
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": 0, "msgid": body.MsgID})
+	})
+	return server
+}
+
+// enterSession 添加一条进入会话事件
+func (r *welcomeServer) enterSession(msgID, openKFID, scene, code string, sendTime time.Time) {
+	r.msgList = append(r.msgList, map[string]interface{}{
+		"msgid":     msgID,
+		"send_time": sendTime.Unix(),
+		"origin":    4,
+		"msgtype":   "event",
+		"event": map[string]string{
+			"event_type":      "enter_session",
+			"open_kfid":       openKFID,
+			"external_userid": "user-" + msgID,
+			"scene":           scene,
+			"welcome_code":    code,
+		},
+	})
+}
+
+func (r *welcomeServer) sentCodes() map[string]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sent := make(map[string]string, len(r.sent))
+	for code, content := range r.sent {
+		sent[code] = content
+	}
+	return sent
+}
+
+func TestWelcomeResponderRules(t *testing.T) {
+	server := newWelcomeServer(t)
+	now := time.Now()
+	server.enterSession("m1", "kf1", "ad", "code-ad", now)
+	server.enterSession("m2", "kf2", "", "code-kf2", now)
+	server.enterSession("m3", "kf1", "", "code-default", now)
+	server.enterSession("m4", "kf1", "ad", "", now)
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	var results []WelcomeResult
+	mutex := sync.Mutex{}
+	responder := client.NewWelcomeResponder(WelcomeResponderOptions{
+		Rules: []WelcomeRule{
+			{Scene: "ad", Builder: WelcomeText("ad")},
+			{OpenKFID: "kf2", Builder: WelcomeText("kf2")},
+			{OpenKFID: "kf2", Builder: WelcomeText("unreachable")},
+			{Builder: WelcomeText("default")},
+		},
+		OnResult: func(result WelcomeResult) {
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+		},
+	})
+	if _, err := client.SyncMsg(SyncMsgOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	responder.Wait()
+
+	want := map[string]string{"code-ad": "ad", "code-kf2": "kf2", "code-default": "default"}
+	if sent := server.sentCodes(); len(sent) != len(want) {
+		t.Fatalf("sent = %v, want %v", sent, want)
+	} else {
+		for code, content := range want {
+			if sent[code] != content {
+				t.Fatalf("sent = %v, want %v", sent, want)
+			}
+		}
+	}
+	if stats := responder.Stats(); stats != (WelcomeStats{Sent: 3}) {
+		t.Fatalf("Stats() = %+v, want 3 sent", stats)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ExternalUserID < results[j].ExternalUserID })
+	first := results[0]
+	if first.Status != WelcomeStatusSent || first.MsgID == "" || first.OpenKFID != "kf1" || first.Scene != "ad" ||
+		!first.Deadline.Equal(time.Unix(now.Unix(), 0).Add(welcomeCodeExpireTime)) {
+		t.Fatalf("result = %+v", first)
+	}
+}
+
+func TestWelcomeResponderExpiredAndFailed(t *testing.T) {
+	server := newWelcomeServer(t)
+	server.failCode = "code-fail"
+	server.enterSession("m1", "kf", "", "code-expired", time.Now().Add(-welcomeCodeExpireTime-time.Second))
+	server.enterSession("m2", "kf", "", "code-fail", time.Now())
+	client := newTestClient(t, Options{CorpID: "corp"})
+
+	var results []WelcomeResult
+	mutex := sync.Mutex{}
+	responder := client.NewWelcomeResponder(WelcomeResponderOptions{
+		Rules: []WelcomeRule{{Builder: WelcomeText("hello")}},
+		OnResult: func(result WelcomeResult) {
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+		},
+	})
+	if _, err := client.SyncMsg(SyncMsgOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	responder.Wait()
+
+	if sent := server.sentCodes(); len(sent) != 0 {
+		t.Fatalf("sent = %v, want none", sent)
+	}
+	if stats := responder.Stats(); stats != (WelcomeStats{Expired: 1, Failed: 1}) {
+		t.Fatalf("Stats() = %+v, want 1 expired and 1 failed", stats)
+	}
+	for _, result := range results {
+		switch result.ExternalUserID {
+		case "user-m1":
+			if result.Status != WelcomeStatusExpired || result.Err != nil {
+				t.Fatalf("expired result = %+v", result)
+			}
+		case "user-m2":
+			if result.Status != WelcomeStatusFailed || result.Err == nil {
+				t.Fatalf("failed result = %+v", result)
+			}
+		}
+	}
+}
+
+func TestWelcomeResponderSkipsRedeliveredEvents(t *testing.T) {
+	tests := []struct {
+		name            string
+		dedupExpireTime time.Duration
+	}{
+		{name: "without dedup"},
+		{name: "with dedup", dedupExpireTime: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWelcomeServer(t)
+			server.enterSession("m1", "kf", "", "code", time.Now())
+			client := newTestClient(t, Options{CorpID: "corp", DedupExpireTime: tt.dedupExpireTime})
+			responder := client.NewWelcomeResponder(WelcomeResponderOptions{Rules: []WelcomeRule{{Builder: WelcomeText("hello")}}})
+
+			for i := 0; i < 2; i++ {
+				if _, err := client.SyncMsg(SyncMsgOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			responder.Wait()
+			if stats := responder.Stats(); stats != (WelcomeStats{Sent: 1}) {
+				t.Fatalf("Stats() = %+v, want the welcome sent once", stats)
+			}
+		})
+	}
+}